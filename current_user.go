@@ -0,0 +1,45 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type CurrentUserAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (c CurrentUserAPI) op(method string) Operation {
+	return Operation{Resource: "CurrentUserAPI", Method: method}
+}
+
+func (c CurrentUserAPI) GetCurrentUser() (User, error) {
+	var result User
+	return result, c.freckle.do(c.op("GetCurrentUser"), "GET", "/current_user", nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (c CurrentUserAPI) GetEntries(fns ...ParameterSetter) (EntriesPage, error) {
+	result := emptyEntriesPage(c.freckle)
+	return result, c.freckle.do(c.op("GetEntries"), "GET", "/current_user/entries", parameters(fns), nil, result.onResponse)
+}
+
+func (c CurrentUserAPI) GetTimers() ([]Timer, error) {
+	var result []Timer
+	return result, c.freckle.do(c.op("GetTimers"), "GET", "/current_user/timers", nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (c CurrentUserAPI) GetExpenses(fns ...ParameterSetter) (ExpensesPage, error) {
+	result := emptyExpensesPage(c.freckle)
+	return result, c.freckle.do(c.op("GetExpenses"), "GET", "/current_user/expenses", parameters(fns), nil, result.onResponse)
+}
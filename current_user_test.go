@@ -0,0 +1,56 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCurrentUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/current_user", response(single_user)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	user, err := f.CurrentUserAPI().GetCurrentUser()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 5538, user.Id, "User id mismatch")
+}
+
+func TestGetCurrentUserEntries(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/current_user/entries", response(array_of_entries)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.CurrentUserAPI().GetEntries()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
+}
+
+func TestGetCurrentUserTimers(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/current_user/timers", response(array_of_timers)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	timers, err := f.CurrentUserAPI().GetTimers()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(timers), "Should have one timer")
+}
+
+func TestGetCurrentUserExpenses(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/current_user/expenses", response(array_of_expenses)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.CurrentUserAPI().GetExpenses()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one expense")
+}
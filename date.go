@@ -0,0 +1,74 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar date without a time or time zone, sent as YYYY-MM-DD
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// Get the date for a year, month and day. Values out of range are
+// normalized the same way as time.Date does, e.g. October 32 is November 1.
+func NewDate(year int, month time.Month, day int) Date {
+	return DateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// Get the date of a time in its own location
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// Parse a YYYY-MM-DD date
+func ParseDate(s string) (Date, error) {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return Date{}, err
+	}
+	return DateOf(t), nil
+}
+
+// Check if the date has not been set
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// Get the time at midnight of the date in a location
+func (d Date) In(loc *time.Location) time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc)
+}
+
+// Get the date as YYYY-MM-DD, or an empty string if it has not been set
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.In(time.UTC).Format(dateLayout)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(data []byte) error {
+	if len(data) == 0 {
+		*d = Date{}
+		return nil
+	}
+	date, err := ParseDate(string(data))
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDate(t *testing.T) {
+	date, err := ParseDate("2014-12-18")
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, Date{2014, time.December, 18}, date)
+	assert.Equal(t, "2014-12-18", date.String())
+	assert.Equal(t, date, DateOf(time.Date(2014, 12, 18, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, NewDate(2014, 11, 1), NewDate(2014, 10, 32), "Date should be normalized")
+
+	_, err = ParseDate("18/12/2014")
+	assert.NotNil(t, err, "Error should not be nil")
+
+	assert.True(t, Date{}.IsZero(), "Date should be zero")
+	assert.Equal(t, "", Date{}.String())
+}
+
+func TestDateJSON(t *testing.T) {
+	var entry Entry
+	assert.Nil(t, json.Unmarshal([]byte(`{"date": "2012-01-09", "created_at": "2012-01-09T08:33:29Z"}`), &entry))
+	assert.Equal(t, NewDate(2012, 1, 9), entry.Date)
+	assert.Equal(t, time.Date(2012, 1, 9, 8, 33, 29, 0, time.UTC), entry.CreatedAt)
+
+	data, err := json.Marshal(entry)
+	assert.Nil(t, err, "Error should be nil")
+	assert.JSONEq(t, `{"date": "2012-01-09", "created_at": "2012-01-09T08:33:29Z"}`, string(data), "Should marshal to the same wire format")
+
+	assert.Nil(t, json.Unmarshal([]byte(`{"date": null}`), &entry))
+	assert.Nil(t, json.Unmarshal([]byte(`{"date": ""}`), &entry))
+	assert.True(t, entry.Date.IsZero(), "Date should be zero")
+	assert.NotNil(t, json.Unmarshal([]byte(`{"date": "January 9th"}`), &entry))
+}
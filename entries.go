@@ -5,6 +5,7 @@
 package freckle
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,76 +15,158 @@ type EntriesAPI struct {
 	freckle *Freckle
 }
 
+// describe an operation of this API for the interceptors
+func (e EntriesAPI) op(method string) Operation {
+	return Operation{Resource: "EntriesAPI", Method: method}
+}
+
 func (e EntriesAPI) ListEntries(fns ...ParameterSetter) (EntriesPage, error) {
+	return e.list(e.freckle.context(), e.op("ListEntries"), fns)
+}
+
+// List the entries like ListEntries, using the context provided for this
+// request only. Unlike with Freckle.WithContext, the page returned does not
+// keep the context, use its NextContext method to fetch the next page.
+func (e EntriesAPI) ListEntriesContext(ctx context.Context, fns ...ParameterSetter) (EntriesPage, error) {
+	return e.list(ctx, e.op("ListEntries"), fns)
+}
+
+// List the entries matching a typed query. Additional ParameterSetter
+// functions can be used for parameters the query doesn't cover.
+func (e EntriesAPI) QueryEntries(q *EntriesQuery, fns ...ParameterSetter) (EntriesPage, error) {
+	return e.query(e.freckle.context(), e.op("QueryEntries"), q, fns)
+}
+
+// Like QueryEntries, using the context provided for this request only
+func (e EntriesAPI) QueryEntriesContext(ctx context.Context, q *EntriesQuery, fns ...ParameterSetter) (EntriesPage, error) {
+	return e.query(ctx, e.op("QueryEntries"), q, fns)
+}
+
+// List all entries matching a typed query, following the pagination. A nil
+// query lists all entries. Fails with ErrTooManyItems if there are more
+// than maxItems entries, see Page.Collect.
+func (e EntriesAPI) ListAllEntries(q *EntriesQuery, maxItems int, fns ...ParameterSetter) ([]Entry, error) {
+	return e.ListAllEntriesContext(e.freckle.context(), q, maxItems, fns...)
+}
+
+// Like ListAllEntries, using the context provided for this request and for
+// fetching the subsequent pages
+func (e EntriesAPI) ListAllEntriesContext(ctx context.Context, q *EntriesQuery, maxItems int, fns ...ParameterSetter) ([]Entry, error) {
+	if q == nil {
+		q = NewEntriesQuery()
+	}
+	page, err := e.query(ctx, e.op("ListAllEntries"), q, fns)
+	if err != nil {
+		return nil, err
+	}
+	return page.collect(ctx, maxItems)
+}
+
+// list the entries for an operation
+func (e EntriesAPI) list(ctx context.Context, op Operation, fns []ParameterSetter) (EntriesPage, error) {
 	result := emptyEntriesPage(e.freckle)
-	return result, e.freckle.do("GET", "/entries", parameters(fns), nil, result.onResponse)
+	return result, e.freckle.doContext(ctx, op, "GET", "/entries", parameters(fns), nil, result.onResponse)
+}
+
+// list the entries matching a typed query for an operation
+func (e EntriesAPI) query(ctx context.Context, op Operation, q *EntriesQuery, fns []ParameterSetter) (EntriesPage, error) {
+	if err := q.Validate(); err != nil {
+		return emptyEntriesPage(e.freckle), err
+	}
+	return e.list(ctx, op, append([]ParameterSetter{q.apply}, fns...))
 }
 
 func emptyEntriesPage(f *Freckle) EntriesPage {
-	return EntriesPage{freckle: f}
+	return EntriesPage{Page: emptyPage[Entry](f, "EntriesPage")}
 }
 
 func (p *EntriesPage) onResponse(data []byte, resp *http.Response) error {
-	links := pagelinks(resp.Header.Get("Link"))
-	var entries []Entry
-
-	err := json.Unmarshal(data, &entries)
-	p.links = links
-	p.Entries = entries
+	err := p.Page.onResponse(data, resp)
+	p.Entries = p.Items
 	return err
 }
 
 func (e EntriesAPI) GetEntry(id int) (Entry, error) {
+	return e.GetEntryContext(e.freckle.context(), id)
+}
+
+// Like GetEntry, using the context provided for this request only
+func (e EntriesAPI) GetEntryContext(ctx context.Context, id int) (Entry, error) {
 	var result Entry
-	return result, e.freckle.do("GET", fmt.Sprintf("/entries/%d", id), nil, nil,
+	return result, e.freckle.doContext(ctx, e.op("GetEntry"), "GET", fmt.Sprintf("/entries/%d", id), nil, nil,
 		func(data []byte, resp *http.Response) error {
 			return json.Unmarshal(data, &result)
 		})
 }
 
-func (e EntriesAPI) CreateEntry(date string, minutes int, fns ...InputSetter) (Entry, error) {
+func (e EntriesAPI) CreateEntry(date Date, minutes int, fns ...InputSetter) (Entry, error) {
+	return e.CreateEntryContext(e.freckle.context(), date, minutes, fns...)
+}
+
+// Like CreateEntry, using the context provided for this request only
+func (e EntriesAPI) CreateEntryContext(ctx context.Context, date Date, minutes int, fns ...InputSetter) (Entry, error) {
 	is := inputs(fns)
 	is["date"] = date
 	is["minutes"] = minutes
 
 	var result Entry
-	return result, e.freckle.do("POST", "/entries", nil, is,
+	return result, e.freckle.doContext(ctx, e.op("CreateEntry"), "POST", "/entries", nil, is,
 		func(output []byte, resp *http.Response) error {
 			return json.Unmarshal(output, &result)
 		})
 }
 
 func (e EntriesAPI) EditEntry(id int, fns ...InputSetter) (Entry, error) {
+	return e.EditEntryContext(e.freckle.context(), id, fns...)
+}
+
+// Like EditEntry, using the context provided for this request only
+func (e EntriesAPI) EditEntryContext(ctx context.Context, id int, fns ...InputSetter) (Entry, error) {
 	var result Entry
-	return result, e.freckle.do("PUT", fmt.Sprintf("/entries/%d", id), nil, inputs(fns),
+	return result, e.freckle.doContext(ctx, e.op("EditEntry"), "PUT", fmt.Sprintf("/entries/%d", id), nil, inputs(fns),
 		func(output []byte, resp *http.Response) error {
 			return json.Unmarshal(output, &result)
 		})
 }
 
-func (e EntriesAPI) MarkAsInvoiced(date string, id int) error {
+func (e EntriesAPI) MarkAsInvoiced(date Date, id int) error {
+	return e.MarkAsInvoicedContext(e.freckle.context(), date, id)
+}
+
+// Like MarkAsInvoiced, using the context provided for this request only
+func (e EntriesAPI) MarkAsInvoicedContext(ctx context.Context, date Date, id int) error {
 	is := make(Inputs)
 	is["date"] = date
 
-	return e.freckle.do("PUT", fmt.Sprintf("/entries/%d/invoiced_outside_of_freckle", id), nil, is,
+	return e.freckle.doContext(ctx, e.op("MarkAsInvoiced"), "PUT", fmt.Sprintf("/entries/%d/invoiced_outside_of_freckle", id), nil, is,
 		func(output []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
-func (e EntriesAPI) MarkMultipleAsInvoiced(date string, id ...int) error {
+func (e EntriesAPI) MarkMultipleAsInvoiced(date Date, id ...int) error {
+	return e.MarkMultipleAsInvoicedContext(e.freckle.context(), date, id...)
+}
+
+// Like MarkMultipleAsInvoiced, using the context provided for this request only
+func (e EntriesAPI) MarkMultipleAsInvoicedContext(ctx context.Context, date Date, id ...int) error {
 	is := make(Inputs)
 	is["date"] = date
 	is["entry_ids"] = id
 
-	return e.freckle.do("PUT", "/entries/invoiced_outside_of_freckle", nil, is,
+	return e.freckle.doContext(ctx, e.op("MarkMultipleAsInvoiced"), "PUT", "/entries/invoiced_outside_of_freckle", nil, is,
 		func(output []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (e EntriesAPI) DeleteEntry(id int) error {
-	return e.freckle.do("DELETE", fmt.Sprintf("/entries/%d", id), nil, nil,
+	return e.DeleteEntryContext(e.freckle.context(), id)
+}
+
+// Like DeleteEntry, using the context provided for this request only
+func (e EntriesAPI) DeleteEntryContext(ctx context.Context, id int) error {
+	return e.freckle.doContext(ctx, e.op("DeleteEntry"), "DELETE", fmt.Sprintf("/entries/%d", id), nil, nil,
 		func(output []byte, resp *http.Response) error {
 			return nil
 		})
@@ -5,10 +5,13 @@
 package freckle
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -62,14 +65,226 @@ func TestListEntriesThroughChannel(t *testing.T) {
 	assert.Equal(t, 10, items, "Should have read 10 pages with 1 item each")
 }
 
+func TestListEntriesThroughIterator(t *testing.T) {
+	page := 0
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		page = page + 1
+		if page == 3 {
+			w.WriteHeader(500)
+			response(server_error)(w, r)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, page+1))
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	ep, err := f.EntriesAPI().ListEntries()
+	assert.Nil(t, err, "Error should be nil")
+
+	items := 0
+	var failure error
+	for _, err := range ep.All() {
+		if err != nil {
+			failure = err
+			break
+		}
+		items = items + 1
+	}
+	assert.Equal(t, 2, items, "Should have read 2 pages with 1 item each")
+	assert.NotNil(t, failure, "Failing to fetch page 3 should have been reported")
+}
+
+func TestStopReadingEntriesEarly(t *testing.T) {
+	requests := 0
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		requests = requests + 1
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, requests+1))
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	ep, err := f.EntriesAPI().ListEntries()
+	assert.Nil(t, err, "Error should be nil")
+
+	// breaking out of the iterator should not fetch any more pages
+	for _, err := range ep.All() {
+		assert.Nil(t, err, "Error should be nil")
+		break
+	}
+	assert.Equal(t, 1, requests, "Should not have fetched the next page")
+
+	// stopping the stream should close the channel
+	entries, stop := ep.StreamEntries()
+	<-entries
+	stop()
+	for _ = range entries {
+	}
+}
+
+func TestListEntriesWithCancelledContext(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries", response(array_of_entries)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := f.WithContext(ctx).EntriesAPI().ListEntries()
+	assert.NotNil(t, err, "Error should not be nil")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestListEntriesContext(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+	var ops []string
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		ops = append(ops, op.String())
+		return next(req)
+	})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.EntriesAPI().ListEntriesContext(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	page, err := f.EntriesAPI().ListEntriesContext(ctx)
+	assert.Nil(t, err, "Error should be nil")
+	cancel()
+
+	// the page does not keep the context of the call that fetched it
+	_, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+
+	_, err = page.NextContext(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+	next, err := page.NextContext(context.Background())
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(next.Entries), "Should have one entry")
+
+	// the context variants report the same operations as the plain calls
+	assert.Equal(t, []string{"EntriesAPI.ListEntries", "EntriesAPI.ListEntries", "EntriesPage.Next", "EntriesPage.Next", "EntriesPage.Next"}, ops)
+}
+
+func TestListEntriesThroughChannelWithContext(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ep, err := f.WithContext(ctx).EntriesAPI().ListEntries()
+	assert.Nil(t, err, "Error should be nil")
+
+	// the server always returns a next page, so only cancelling stops the channel
+	items := 0
+	for _ = range ep.AllEntries() {
+		items = items + 1
+		if items == 3 {
+			cancel()
+		}
+	}
+	assert.True(t, items >= 3, "Should have read at least 3 entries")
+}
+
+func TestListAllEntries(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "37396", r.URL.Query().Get("projects"), "Should send the query")
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?projects=37396&page=%d>; rel=\"next\"", ts.URL, r.URL.Path, max(page, 1)+1))
+		}
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	q := NewEntriesQuery().Projects(37396)
+	entries, err := f.EntriesAPI().ListAllEntries(q, 3)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 3, len(entries), "Should have collected the entries on all pages")
+
+	entries, err = f.EntriesAPI().ListAllEntries(q, 2)
+	assert.ErrorIs(t, err, ErrTooManyItems)
+	assert.Nil(t, entries, "Should not return the entries collected so far")
+
+	_, err = f.EntriesAPI().ListAllEntries(NewEntriesQuery().Projects(-1), 10)
+	assert.NotNil(t, err, "Invalid query should fail")
+}
+
+func TestListAllEntriesContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 2 {
+			// the context is used for the subsequent pages as well
+			cancel()
+		}
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, max(page, 1)+1))
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().ListAllEntriesContext(ctx, nil, 10)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestGetEntry(t *testing.T) {
 	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", response(single_entry)))
 	defer ts.Close()
 
 	f := letsTestFreckle(ts)
 
-	_, err := f.EntriesAPI().GetEntry(1)
+	entry, err := f.EntriesAPI().GetEntry(1)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, NewDate(2012, 1, 9), entry.Date, "Date mismatch")
+	assert.Equal(t, time.Date(2012, 1, 9, 8, 33, 29, 0, time.UTC), entry.CreatedAt, "Created at mismatch")
+	assert.Equal(t, time.Date(2012, 1, 10, 8, 33, 29, 0, time.UTC), entry.InvoicedAt, "Invoiced at mismatch")
+}
+
+func TestGetEntryContext(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", response(single_entry)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.EntriesAPI().GetEntryContext(cancelled, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	entry, err := f.EntriesAPI().GetEntryContext(context.Background(), 1)
 	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, NewDate(2012, 1, 9), entry.Date, "Date mismatch")
 }
 
 func TestCreateEntry(t *testing.T) {
@@ -78,7 +293,7 @@ func TestCreateEntry(t *testing.T) {
 
 	f := letsTestFreckle(ts)
 
-	_, err := f.EntriesAPI().CreateEntry("2014-12-18", 60, func(i Inputs) {
+	_, err := f.EntriesAPI().CreateEntry(NewDate(2014, 12, 18), 60, func(i Inputs) {
 		i["description"] = "Very hard #support question"
 	})
 	assert.Nil(t, err, "Error should be nil")
@@ -102,7 +317,7 @@ func TestMarkAsInvoiced(t *testing.T) {
 
 	f := letsTestFreckle(ts)
 
-	err := f.EntriesAPI().MarkAsInvoiced("2014-12-18", 1)
+	err := f.EntriesAPI().MarkAsInvoiced(NewDate(2014, 12, 18), 1)
 	assert.Nil(t, err, "Error should be nil")
 }
 
@@ -112,7 +327,7 @@ func TestMarkMultipleAsInvoiced(t *testing.T) {
 
 	f := letsTestFreckle(ts)
 
-	err := f.EntriesAPI().MarkMultipleAsInvoiced("2014-12-18", 1, 2, 3)
+	err := f.EntriesAPI().MarkMultipleAsInvoiced(NewDate(2014, 12, 18), 1, 2, 3)
 	assert.Nil(t, err, "Error should be nil")
 }
 
@@ -126,6 +341,8 @@ func TestDeleteEntry(t *testing.T) {
 	assert.Nil(t, err, "Error should be nil")
 }
 
+const server_error = `{"message":"Internal Server Error"}`
+
 const array_of_entries = `[
   {
     "id": 1,
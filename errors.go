@@ -0,0 +1,66 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Sentinel errors to check a FreckleError against with errors.Is
+var (
+	ErrUnauthorized = errors.New("freckle: unauthorized")
+	ErrNotFound     = errors.New("freckle: not found")
+	ErrValidation   = errors.New("freckle: validation failed")
+	ErrRateLimited  = errors.New("freckle: rate limited")
+)
+
+// Error returned by the collect-all helpers when a listing has more items
+// than the maximum asked for
+var ErrTooManyItems = errors.New("freckle: too many items")
+
+// Get the error message for a Freckle API error
+func (e FreckleError) Error() string {
+	if e.StatusCode == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s %s: HTTP %d %s", e.Method, e.URL, e.StatusCode, e.Message)
+}
+
+// Check if the Freckle API error matches one of the sentinel errors
+func (e FreckleError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity || len(e.Errors) > 0
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Is the error caused by a missing or invalid API token?
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// Is the error caused by a resource that doesn't exist?
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// Is the error caused by invalid input or parameters?
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// Is the error caused by sending too many requests?
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
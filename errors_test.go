@@ -0,0 +1,79 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFoundError(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		response(`{"message":"Not Found"}`)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().GetEntry(1)
+	assert.True(t, IsNotFound(err), "Should be a not found error")
+	assert.False(t, IsUnauthorized(err), "Should not be an unauthorized error")
+
+	var fe FreckleError
+	assert.True(t, errors.As(err, &fe), "Should be a FreckleError")
+	assert.Equal(t, 404, fe.StatusCode, "Status code mismatch")
+	assert.Equal(t, "GET", fe.Method, "Method mismatch")
+	assert.Equal(t, ts.URL+"/entries/1", fe.URL, "URL mismatch")
+	assert.Equal(t, "Not Found", fe.Message, "Message mismatch")
+}
+
+func TestUnauthorizedErrorWithoutJSON(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+		response("<html>Bad token</html>")(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().GetEntry(1)
+	assert.True(t, IsUnauthorized(err), "Should be an unauthorized error")
+
+	var fe FreckleError
+	assert.True(t, errors.As(err, &fe), "Should be a FreckleError")
+	assert.Equal(t, "Unauthorized", fe.Message, "Should fall back to the HTTP status text")
+	assert.Contains(t, string(fe.Body), "Bad token", "Should keep the raw body")
+}
+
+func TestValidationError(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		response(invalid_billing_code)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ProjectsAPI().CreateProject("Gear GmbH")
+	assert.True(t, IsValidation(err), "Should be a validation error")
+	assert.True(t, errors.Is(err, ErrValidation), "Should match ErrValidation")
+
+	var fe FreckleError
+	assert.True(t, errors.As(err, &fe), "Should be a FreckleError")
+	assert.Equal(t, 1, len(fe.Errors), "Should have one error detail")
+	assert.Equal(t, "billable", fe.Errors[0].Field, "Error field mismatch")
+}
+
+func TestRateLimitedError(t *testing.T) {
+	err := FreckleError{StatusCode: 429, Message: "Too Many Requests"}
+	assert.True(t, IsRateLimited(err), "Should be a rate limited error")
+	assert.False(t, IsNotFound(err), "Should not be a not found error")
+	assert.False(t, IsRateLimited(errors.New("something else")), "Should not match other errors")
+}
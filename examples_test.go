@@ -6,6 +6,7 @@ package freckle_test
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gertv/go-freckle"
 )
@@ -24,13 +25,25 @@ func Example() {
 // additional input. This input can be provided through an
 // anonymous function to add values to the Inputs object.
 func ExampleInputSetter(f freckle.Freckle) {
-	f.EntriesAPI().CreateEntry("2014-12-22", 60, func(i freckle.Inputs) {
+	f.EntriesAPI().CreateEntry(freckle.NewDate(2014, 12, 22), 60, func(i freckle.Inputs) {
 		// here you can add addtional input data to your API call
 		i["description"] = "My neat #development issue"
 		i["project_name"] = "Customer Project"
 	})
 }
 
+// Instead of adding values to the Inputs object yourself, you can also
+// use the typed EntryInput and ProjectInput structs.
+func ExampleEntryInput() {
+	f := freckle.LetsFreckle("mycompany", "MyFreckleAPIV2Token")
+
+	f.EntriesAPI().CreateEntry(freckle.NewDate(2014, 12, 22), 60, freckle.EntryInput{
+		Description: "My neat #development issue",
+		ProjectName: "Customer Project",
+		Billable:    freckle.Some(false),
+	}.Set)
+}
+
 // Some functions, like the query functions, require
 // additional parameters. These parameters can be provided through
 // an anonymous function to add values to the Parameters object.
@@ -70,3 +83,55 @@ func ExampleProjectsPage_AllProjects(f freckle.Freckle) {
 		fmt.Println("Project name is " + project.Name)
 	}
 }
+
+// The All method returns an iterator over all entries on the current
+// and subsequent pages. Contrary to the AllEntries channel, it also
+// reports an error when one of the subsequent pages can not be fetched.
+func ExampleEntriesPage_All() {
+	f := freckle.LetsFreckle("mycompany", "MyFreckleAPIV2Token")
+	page, _ := f.EntriesAPI().ListEntries()
+
+	minutes := 0
+	for entry, err := range page.All() {
+		if err != nil {
+			fmt.Println("Unable to read all entries: " + err.Error())
+			return
+		}
+		minutes += entry.Minutes
+	}
+	fmt.Printf("Logged %d minutes in total\n", minutes)
+}
+
+// For large listings, the next pages can be fetched in parallel while
+// the entries are still read in order.
+func ExamplePage_Parallel() {
+	f := freckle.LetsFreckle("mycompany", "MyFreckleAPIV2Token")
+	page, _ := f.EntriesAPI().ListEntries()
+
+	for entry, err := range page.Parallel(4) {
+		if err != nil {
+			fmt.Println("Unable to read all entries: " + err.Error())
+			return
+		}
+		fmt.Printf("%s: %d minutes\n", entry.Date, entry.Minutes)
+	}
+}
+
+// Instead of setting raw parameters, entries can also be listed with
+// a typed query. The query is validated before it gets sent.
+func ExampleEntriesQuery() {
+	f := freckle.LetsFreckle("mycompany", "MyFreckleAPIV2Token")
+
+	q := freckle.NewEntriesQuery().
+		From(time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC)).
+		To(time.Date(2014, 11, 30, 0, 0, 0, 0, time.UTC)).
+		Projects(37396, 37397).
+		Billable(true)
+
+	page, err := f.EntriesAPI().QueryEntries(q)
+	if err != nil {
+		fmt.Println("Unable to list entries: " + err.Error())
+		return
+	}
+	fmt.Printf("Found %d entries on the first page\n", len(page.Entries))
+}
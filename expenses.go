@@ -0,0 +1,85 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type ExpensesAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (e ExpensesAPI) op(method string) Operation {
+	return Operation{Resource: "ExpensesAPI", Method: method}
+}
+
+func (e ExpensesAPI) ListExpenses(fns ...ParameterSetter) (ExpensesPage, error) {
+	result := emptyExpensesPage(e.freckle)
+	return result, e.freckle.do(e.op("ListExpenses"), "GET", "/expenses", parameters(fns), nil, result.onResponse)
+}
+
+func emptyExpensesPage(f *Freckle) ExpensesPage {
+	return emptyPage[Expense](f, "ExpensesPage")
+}
+
+func (e ExpensesAPI) GetExpense(id int) (Expense, error) {
+	var result Expense
+	return result, e.freckle.do(e.op("GetExpense"), "GET", fmt.Sprintf("/expenses/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (e ExpensesAPI) CreateExpense(date Date, amount float64, fns ...InputSetter) (Expense, error) {
+	is := inputs(fns)
+	is["date"] = date
+	is["amount"] = amount
+
+	var result Expense
+	return result, e.freckle.do(e.op("CreateExpense"), "POST", "/expenses", nil, is,
+		func(output []byte, resp *http.Response) error {
+			return json.Unmarshal(output, &result)
+		})
+}
+
+func (e ExpensesAPI) EditExpense(id int, fns ...InputSetter) (Expense, error) {
+	var result Expense
+	return result, e.freckle.do(e.op("EditExpense"), "PUT", fmt.Sprintf("/expenses/%d", id), nil, inputs(fns),
+		func(output []byte, resp *http.Response) error {
+			return json.Unmarshal(output, &result)
+		})
+}
+
+func (e ExpensesAPI) MarkAsInvoiced(date Date, id int) error {
+	is := make(Inputs)
+	is["date"] = date
+
+	return e.freckle.do(e.op("MarkAsInvoiced"), "PUT", fmt.Sprintf("/expenses/%d/invoiced_outside_of_freckle", id), nil, is,
+		func(output []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (e ExpensesAPI) MarkMultipleAsInvoiced(date Date, id ...int) error {
+	is := make(Inputs)
+	is["date"] = date
+	is["expense_ids"] = id
+
+	return e.freckle.do(e.op("MarkMultipleAsInvoiced"), "PUT", "/expenses/invoiced_outside_of_freckle", nil, is,
+		func(output []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (e ExpensesAPI) DeleteExpense(id int) error {
+	return e.freckle.do(e.op("DeleteExpense"), "DELETE", fmt.Sprintf("/expenses/%d", id), nil, nil,
+		func(output []byte, resp *http.Response) error {
+			return nil
+		})
+}
@@ -0,0 +1,183 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListExpenses(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/expenses", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(array_of_expenses)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ExpensesAPI().ListExpenses()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one expense")
+	assert.Equal(t, 12.5, page.Items[0].Amount, "Expense amount mismatch")
+	assert.True(t, page.HasNext(), "Should have a next page")
+	assert.False(t, page.HasPrevious(), "Should not have a previous page")
+
+	page, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one expense")
+}
+
+func TestListExpensesThroughChannel(t *testing.T) {
+	page := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/expenses", func(w http.ResponseWriter, r *http.Request) {
+		page += 1
+		if page < 10 {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, page+1))
+		}
+		response(array_of_expenses)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	ep, err := f.ExpensesAPI().ListExpenses()
+	assert.Nil(t, err, "Error should be nil")
+	expenses := 0
+	for _ = range ep.AllItems() {
+		expenses += 1
+	}
+	assert.Equal(t, 10, expenses, "Should have read 10 expenses")
+}
+
+func TestGetExpense(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/expenses/1", response(single_expense)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ExpensesAPI().GetExpense(1)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestCreateExpense(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/expenses", response(single_expense)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ExpensesAPI().CreateExpense(NewDate(2014, 12, 18), 12.5, func(i Inputs) {
+		i["description"] = "Train ticket"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestEditExpense(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/expenses/1", response(single_expense)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ExpensesAPI().EditExpense(1, func(i Inputs) {
+		i["description"] = "Bus ticket"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestMarkExpenseAsInvoiced(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/expenses/1/invoiced_outside_of_freckle", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.ExpensesAPI().MarkAsInvoiced(NewDate(2014, 12, 18), 1)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestMarkMultipleExpensesAsInvoiced(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/expenses/invoiced_outside_of_freckle", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.ExpensesAPI().MarkMultipleAsInvoiced(NewDate(2014, 12, 18), 1, 2, 3)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeleteExpense(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/expenses/1", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.ExpensesAPI().DeleteExpense(1)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+const array_of_expenses = `[
+  {
+    "id": 1,
+    "date": "2012-01-09",
+    "user": {
+      "id": 5538,
+      "email": "john.test@test.com",
+      "first_name": "John",
+      "last_name": "Test",
+      "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+      "url": "https://api.letsfreckle.com/v2/users/5538"
+    },
+    "amount": 12.5,
+    "description": "Train ticket",
+    "project": {
+      "id": 37396,
+      "name": "Gear GmbH",
+      "billing_increment": 10,
+      "enabled": true,
+      "billable": true,
+      "color": "#ff9898",
+      "url": "https://api.letsfreckle.com/v2/projects/37396"
+    },
+    "invoiced_at": "2012-01-10T08:33:29Z",
+    "receipt_url": "https://api.letsfreckle.com/v2/expenses/1/receipt",
+    "url": "https://api.letsfreckle.com/v2/expenses/1",
+    "created_at": "2012-01-09T08:33:29Z",
+    "updated_at": "2012-01-09T08:33:29Z"
+  }
+]`
+
+const single_expense = `{
+  "id": 1,
+  "date": "2012-01-09",
+  "user": {
+    "id": 5538,
+    "email": "john.test@test.com",
+    "first_name": "John",
+    "last_name": "Test",
+    "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+    "url": "https://api.letsfreckle.com/v2/users/5538"
+  },
+  "amount": 12.5,
+  "description": "Train ticket",
+  "project": {
+    "id": 37396,
+    "name": "Gear GmbH",
+    "billing_increment": 10,
+    "enabled": true,
+    "billable": true,
+    "color": "#ff9898",
+    "url": "https://api.letsfreckle.com/v2/projects/37396"
+  },
+  "invoiced_at": "2012-01-10T08:33:29Z",
+  "receipt_url": "https://api.letsfreckle.com/v2/expenses/1/receipt",
+  "url": "https://api.letsfreckle.com/v2/expenses/1",
+  "created_at": "2012-01-09T08:33:29Z",
+  "updated_at": "2012-01-09T08:33:29Z"
+}`
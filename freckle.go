@@ -9,26 +9,81 @@
 package freckle
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
+	"os"
 )
 
 type Freckle struct {
 	subdomain, key string
-	debug          bool
 	client         *http.Client
 	base           string
+	ctx            context.Context
+	retry          RetryPolicy
+	limiter        *limiter
+	logger         *slog.Logger
+	logBodies      bool
+	debug          *debugMode
+	interceptors   []Interceptor
+	// interceptors called once per operation, around all attempts
+	operationInterceptors []Interceptor
 }
 
 // Start using the API here -
 func LetsFreckle(subdomain, key string) Freckle {
-	return Freckle{subdomain, key, false, &http.Client{}, "https://api.letsfreckle.com/v2"}
+	return Freckle{
+		subdomain: subdomain,
+		key:       key,
+		client:    &http.Client{},
+		base:      "https://api.letsfreckle.com/v2",
+		limiter:   newLimiter(),
+	}
 }
 
 // Enable/disable debug mode. When debug mode is enabled,
-// you will get additional logging showing the HTTP requests
-// and responses
+// you will get additional logging on stderr showing the HTTP
+// requests and responses. Use Logger instead to configure
+// your own structured logger. Disabling debug mode restores
+// the logger and body logging that were configured before it
+// was enabled.
 func (f *Freckle) Debug(d bool) {
-	f.debug = d
+	if d && f.debug == nil {
+		f.debug = &debugMode{
+			logger:    slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			previous:  f.logger,
+			logBodies: f.logBodies,
+		}
+		f.logger = f.debug.logger
+		f.logBodies = true
+	} else if !d && f.debug != nil {
+		// keep a logger configured while debugging, but stop logging
+		// the bodies to it unless that was asked for before
+		if f.logger == f.debug.logger {
+			f.logger = f.debug.previous
+		}
+		f.logBodies = f.debug.logBodies
+		f.debug = nil
+	}
+}
+
+// logging set up by Debug, with the settings it replaced
+type debugMode struct {
+	logger    *slog.Logger
+	previous  *slog.Logger
+	logBodies bool
+}
+
+// Configure a structured logger for the HTTP requests sent to the API.
+// Successful requests are logged at debug level, failures at warn or error level.
+func (f *Freckle) Logger(logger *slog.Logger) {
+	f.logger = logger
+}
+
+// Enable/disable logging the headers and bodies of requests and responses
+// at debug level. The API token is always redacted from the headers.
+func (f *Freckle) LogBodies(b bool) {
+	f.logBodies = b
 }
 
 // Configure a custom HTTP client (e.g. to configure a proxy server)
@@ -36,6 +91,51 @@ func (f *Freckle) Client(client *http.Client) {
 	f.client = client
 }
 
+// Configure the policy for retrying failed requests. By default,
+// requests are not retried at all.
+func (f *Freckle) Retry(policy RetryPolicy) {
+	f.retry = policy
+}
+
+// Limit the number of requests per second sent to the API, allowing
+// bursts of up to burst requests. Use a rate of 0 to disable throttling.
+// Regardless of this setting, requests are held back for the time indicated
+// by the Retry-After header when the API responds with 429 Too Many Requests.
+func (f *Freckle) Throttle(requestsPerSecond float64, burst int) {
+	if f.limiter == nil {
+		f.limiter = newLimiter()
+	}
+	f.limiter.configure(requestsPerSecond, burst)
+}
+
+// Get the rate limit information from the last API response
+func (f Freckle) RateLimit() RateLimit {
+	return f.limiter.rateLimit()
+}
+
+// Get a copy of the client that uses the context provided for all its API calls,
+// including the ones made to fetch additional pages. Cancelling the context
+// aborts any pending HTTP request and stops the All... channels.
+//
+// The copy is meant to be used for a single call or a short sequence of calls
+// for one request or task, not to be kept around: pages fetched with it keep
+// using the context as well. To pass a context to a single call instead, use
+// the ...Context variants of the EntriesAPI and ProjectsAPI calls and
+// Page.NextContext.
+func (f Freckle) WithContext(ctx context.Context) Freckle {
+	f.ctx = ctx
+	return f
+}
+
+/*
+Access the LetsFreckle v2 Current User API
+
+more info at http://developer.letsfreckle.com/v2/current_user
+*/
+func (f Freckle) CurrentUserAPI() CurrentUserAPI {
+	return CurrentUserAPI{&f}
+}
+
 /*
 Access the LetsFreckle v2 Entries API
 
@@ -45,6 +145,33 @@ func (f Freckle) EntriesAPI() EntriesAPI {
 	return EntriesAPI{&f}
 }
 
+/*
+Access the LetsFreckle v2 Expenses API
+
+more info at http://developer.letsfreckle.com/v2/expenses
+*/
+func (f Freckle) ExpensesAPI() ExpensesAPI {
+	return ExpensesAPI{&f}
+}
+
+/*
+Access the LetsFreckle v2 Imports API
+
+more info at http://developer.letsfreckle.com/v2/imports
+*/
+func (f Freckle) ImportsAPI() ImportsAPI {
+	return ImportsAPI{&f}
+}
+
+/*
+Access the LetsFreckle v2 Invoices API
+
+more info at http://developer.letsfreckle.com/v2/invoices
+*/
+func (f Freckle) InvoicesAPI() InvoicesAPI {
+	return InvoicesAPI{&f}
+}
+
 /*
 Access the LetsFreckle v2 Projects API
 
@@ -54,9 +181,40 @@ func (f Freckle) ProjectsAPI() ProjectsAPI {
 	return ProjectsAPI{&f}
 }
 
-// Get the error message for a Freckle API error
-func (e FreckleError) Error() string {
-	return e.Message
+/*
+Access the LetsFreckle v2 Project Groups API
+
+more info at http://developer.letsfreckle.com/v2/project_groups
+*/
+func (f Freckle) ProjectGroupsAPI() ProjectGroupsAPI {
+	return ProjectGroupsAPI{&f}
+}
+
+/*
+Access the LetsFreckle v2 Tags API
+
+more info at http://developer.letsfreckle.com/v2/tags
+*/
+func (f Freckle) TagsAPI() TagsAPI {
+	return TagsAPI{&f}
+}
+
+/*
+Access the LetsFreckle v2 Timers API
+
+more info at http://developer.letsfreckle.com/v2/timers
+*/
+func (f Freckle) TimersAPI() TimersAPI {
+	return TimersAPI{&f}
+}
+
+/*
+Access the LetsFreckle v2 Users API
+
+more info at http://developer.letsfreckle.com/v2/users
+*/
+func (f Freckle) UsersAPI() UsersAPI {
+	return UsersAPI{&f}
 }
 
 // Data type to represent values passed to a create, edit, ... calls
@@ -5,9 +5,14 @@
 package freckle
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,3 +50,97 @@ func noContent() func(http.ResponseWriter, *http.Request) {
 		w.WriteHeader(204)
 	}
 }
+
+func TestStructuredLogging(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/entries/1", response(single_entry)))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	f := LetsFreckle(domain, token)
+	f.base = ts.URL
+	f.Logger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	_, err := f.EntriesAPI().EditEntry(1, func(i Inputs) {
+		i["description"] = "Not so hard #support question"
+	})
+	assert.Nil(t, err, "Error should be nil")
+
+	var record map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &record), "Should have logged a single JSON record")
+	assert.Equal(t, "PUT", record["method"], "Method should have been logged")
+	assert.Equal(t, "/entries/1", record["path"], "Path should have been logged")
+	assert.Equal(t, float64(200), record["status"], "Status should have been logged")
+	assert.Contains(t, record, "duration", "Duration should have been logged")
+	assert.NotContains(t, buf.String(), "#support", "Bodies should not be logged by default")
+}
+
+func TestLogBodiesRedactsToken(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/entries/1", response(single_entry)))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	f := LetsFreckle(domain, token)
+	f.base = ts.URL
+	f.Logger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	f.LogBodies(true)
+
+	_, err := f.EntriesAPI().EditEntry(1, func(i Inputs) {
+		i["description"] = "Not so hard #support question"
+	})
+	assert.Nil(t, err, "Error should be nil")
+	assert.Contains(t, buf.String(), "Not so hard #support question", "Request body should have been logged")
+	assert.Contains(t, buf.String(), "REDACTED", "Token should have been redacted")
+	assert.NotContains(t, buf.String(), token, "Token should never be logged")
+}
+
+func TestDebugRestoresLogger(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	f := LetsFreckle(domain, token)
+	f.Logger(logger)
+
+	f.Debug(true)
+	assert.NotEqual(t, logger, f.logger, "Debug should log to stderr")
+	assert.True(t, f.logBodies, "Debug should log bodies")
+
+	f.Debug(false)
+	assert.Equal(t, logger, f.logger, "Disabling debug should restore the logger")
+	assert.False(t, f.logBodies, "Disabling debug should stop logging bodies")
+
+	f.Debug(false)
+	assert.Equal(t, logger, f.logger, "Disabling debug again should keep the logger")
+}
+
+func TestDebugWithCustomLogger(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(io.Discard, nil))
+	f := LetsFreckle(domain, token)
+
+	f.Debug(true)
+	f.Logger(logger)
+	f.Debug(false)
+	assert.Equal(t, logger, f.logger, "Disabling debug should keep the logger configured since")
+	assert.False(t, f.logBodies, "Disabling debug should stop logging bodies")
+}
+
+func TestRequestBodyOnlyReadWhenLogged(t *testing.T) {
+	reads := 0
+	req, _ := http.NewRequest("PUT", "https://api.letsfreckle.com/v2/entries/1", strings.NewReader(`{"minutes": 60}`))
+	getBody := req.GetBody
+	req.GetBody = func() (io.ReadCloser, error) {
+		reads++
+		return getBody()
+	}
+	rt := func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	}
+
+	f := LetsFreckle(domain, token)
+	f.Logger(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+	_, _, err := f.send(rt, req)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 0, reads, "Body should not be read when bodies are not logged")
+
+	f.LogBodies(true)
+	_, _, err = f.send(rt, req)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, reads, "Body should be read to log it")
+}
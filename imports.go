@@ -0,0 +1,41 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type ImportsAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (i ImportsAPI) op(method string) Operation {
+	return Operation{Resource: "ImportsAPI", Method: method}
+}
+
+func (i ImportsAPI) ListImports(fns ...ParameterSetter) ([]Import, error) {
+	var result []Import
+	return result, i.freckle.do(i.op("ListImports"), "GET", "/imports", parameters(fns), nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (i ImportsAPI) GetImport(id int) (Import, error) {
+	var result Import
+	return result, i.freckle.do(i.op("GetImport"), "GET", fmt.Sprintf("/imports/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (i ImportsAPI) GetEntries(id int) (EntriesPage, error) {
+	result := emptyEntriesPage(i.freckle)
+	return result, i.freckle.do(i.op("GetEntries"), "GET", fmt.Sprintf("/imports/%d/entries", id), nil, nil, result.onResponse)
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListImports(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/imports", response(array_of_imports)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	imports, err := f.ImportsAPI().ListImports()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(imports), "Should have one import")
+	assert.Equal(t, "entries.csv", imports[0].FileName, "Import file name mismatch")
+	assert.Equal(t, 5538, imports[0].User.Id, "Import user mismatch")
+}
+
+func TestGetImport(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/imports/8910", response(single_import)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	imp, err := f.ImportsAPI().GetImport(8910)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 42, imp.Entries, "Import entries mismatch")
+}
+
+func TestGetImportEntries(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/imports/8910/entries", response(array_of_entries)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ImportsAPI().GetEntries(8910)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
+	assert.Equal(t, 8910, page.Entries[0].Import.Id, "Entry import mismatch")
+}
+
+const array_of_imports = `[
+  {
+    "id": 8910,
+    "url": "https://api.letsfreckle.com/v2/imports/8910",
+    "file_name": "entries.csv",
+    "state": "completed",
+    "user": {
+      "id": 5538,
+      "email": "john.test@test.com",
+      "first_name": "John",
+      "last_name": "Test",
+      "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+      "url": "https://api.letsfreckle.com/v2/users/5538"
+    },
+    "entries": 42,
+    "entries_url": "https://api.letsfreckle.com/v2/imports/8910/entries",
+    "created_at": "2012-01-09T08:33:29Z",
+    "updated_at": "2012-01-09T08:33:29Z"
+  }
+]`
+
+const single_import = `{
+  "id": 8910,
+  "url": "https://api.letsfreckle.com/v2/imports/8910",
+  "file_name": "entries.csv",
+  "state": "completed",
+  "user": {
+    "id": 5538,
+    "email": "john.test@test.com",
+    "first_name": "John",
+    "last_name": "Test",
+    "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+    "url": "https://api.letsfreckle.com/v2/users/5538"
+  },
+  "entries": 42,
+  "entries_url": "https://api.letsfreckle.com/v2/imports/8910/entries",
+  "created_at": "2012-01-09T08:33:29Z",
+  "updated_at": "2012-01-09T08:33:29Z"
+}`
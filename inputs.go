@@ -0,0 +1,68 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+// Typed input for EntriesAPI.CreateEntry and EntriesAPI.EditEntry. Pass its
+// Set method as an InputSetter, only the fields that have been set are sent.
+type EntryInput struct {
+	Date        Date
+	Minutes     int
+	Description string
+	// Set either the id or the name of the project, not both
+	ProjectId   int
+	ProjectName string
+	UserEmail   string
+	SourceUrl   string
+	Billable    Optional[bool]
+}
+
+// Add the fields that have been set to the Inputs
+func (e EntryInput) Set(i Inputs) {
+	setInput(i, "date", e.Date)
+	setInput(i, "minutes", e.Minutes)
+	setInput(i, "description", e.Description)
+	setInput(i, "project_id", e.ProjectId)
+	setInput(i, "project_name", e.ProjectName)
+	setInput(i, "user_email", e.UserEmail)
+	setInput(i, "source_url", e.SourceUrl)
+	setOptional(i, "billable", e.Billable)
+}
+
+// Typed input for ProjectsAPI.CreateProject and ProjectsAPI.EditProject. Pass
+// its Set method as an InputSetter, only the fields that have been set are sent.
+type ProjectInput struct {
+	Name             string
+	BillingIncrement int
+	Color            string
+	ProjectGroupId   int
+	Billable         Optional[bool]
+	// Set to Null to remove the budget from the project
+	BudgetMinutes Optional[int]
+}
+
+// Add the fields that have been set to the Inputs
+func (p ProjectInput) Set(i Inputs) {
+	setInput(i, "name", p.Name)
+	setInput(i, "billing_increment", p.BillingIncrement)
+	setInput(i, "color", p.Color)
+	setInput(i, "project_group_id", p.ProjectGroupId)
+	setOptional(i, "billable", p.Billable)
+	setOptional(i, "budget_minutes", p.BudgetMinutes)
+}
+
+// set an input value, unless it's the zero value
+func setInput[T comparable](i Inputs, key string, value T) {
+	var zero T
+	if value != zero {
+		i[key] = value
+	}
+}
+
+// set an optional input value, unless it's missing
+func setOptional[T any](i Inputs, key string, value Optional[T]) {
+	if !value.IsZero() {
+		i[key] = value.input()
+	}
+}
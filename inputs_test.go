@@ -0,0 +1,92 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntryInput(t *testing.T) {
+	is := inputs([]InputSetter{EntryInput{
+		Description: "Very hard #support question",
+		ProjectName: "Gear GmbH",
+		UserEmail:   "john.test@test.com",
+		Billable:    Some(false),
+	}.Set})
+
+	assert.Equal(t, Inputs{
+		"description":  "Very hard #support question",
+		"project_name": "Gear GmbH",
+		"user_email":   "john.test@test.com",
+		"billable":     false,
+	}, is)
+}
+
+func TestEntryInputDate(t *testing.T) {
+	is := inputs([]InputSetter{EntryInput{Date: NewDate(2014, 12, 22), Minutes: 60}.Set})
+	data, _ := json.Marshal(is)
+	assert.JSONEq(t, `{"date": "2014-12-22", "minutes": 60}`, string(data), "Date should be sent as YYYY-MM-DD")
+}
+
+func TestProjectInput(t *testing.T) {
+	is := inputs([]InputSetter{ProjectInput{
+		BillingIncrement: 15,
+		Color:            "#ff9898",
+		ProjectGroupId:   3768,
+		BudgetMinutes:    Some(750),
+	}.Set})
+
+	assert.Equal(t, Inputs{
+		"billing_increment": 15,
+		"color":             "#ff9898",
+		"project_group_id":  3768,
+		"budget_minutes":    750,
+	}, is)
+
+	is = inputs([]InputSetter{ProjectInput{BudgetMinutes: Null[int]()}.Set})
+	data, _ := json.Marshal(is)
+	assert.JSONEq(t, `{"budget_minutes": null}`, string(data), "Clearing the budget should send null")
+}
+
+func TestCreateEntryWithEntryInput(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body), "Body should be valid JSON")
+		assert.Equal(t, "2014-12-18", body["date"])
+		assert.Equal(t, float64(60), body["minutes"])
+		assert.Equal(t, float64(37396), body["project_id"])
+		assert.Equal(t, "Very hard #support question", body["description"])
+		response(single_entry)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().CreateEntry(NewDate(2014, 12, 18), 60, EntryInput{
+		Description: "Very hard #support question",
+		ProjectId:   37396,
+	}.Set)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestEditProjectWithProjectInput(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/projects/37396", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body), "Body should be valid JSON")
+		assert.Equal(t, map[string]interface{}{"name": "New Name", "billable": false}, body)
+		response(single_project)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ProjectsAPI().EditProject(37396, ProjectInput{Name: "New Name", Billable: Some(false)}.Set)
+	assert.Nil(t, err, "Error should be nil")
+}
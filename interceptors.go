@@ -0,0 +1,66 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"net/http"
+)
+
+// The API operation an HTTP request is sent for
+type Operation struct {
+	// Type providing the operation, e.g. EntriesAPI or EntriesPage
+	Resource string
+	// Name of the method, e.g. ListEntries or Next
+	Method string
+	// Number of the attempt to send the request, starting at 1
+	Attempt int
+}
+
+// Get the full name of the operation, e.g. EntriesAPI.ListEntries
+func (o Operation) String() string {
+	return o.Resource + "." + o.Method
+}
+
+// Function to send an HTTP request and get the response
+type RoundTrip func(*http.Request) (*http.Response, error)
+
+// Function to intercept every HTTP request sent to the API. It can inspect or
+// modify the request, calls next to continue sending it and can then inspect
+// the response. Interceptors that read the response body have to replace it.
+type Interceptor func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error)
+
+// Add interceptors for all HTTP requests sent to the API. The first
+// interceptor added is the first one to see the request. When a request is
+// retried, these interceptors see every attempt.
+func (f *Freckle) Intercept(interceptors ...Interceptor) {
+	f.interceptors = append(f.interceptors[:len(f.interceptors):len(f.interceptors)], interceptors...)
+}
+
+// Add interceptors for all API operations. Unlike the ones added with
+// Intercept, these interceptors are called once per operation, around all
+// attempts to send its request. The Attempt of the operation is 0, and the
+// response returned by next is the one of the last attempt, with its body
+// already read. The context of the request passed to next is used for all
+// attempts, so it can carry e.g. a span for the whole operation.
+func (f *Freckle) InterceptOperations(interceptors ...Interceptor) {
+	f.operationInterceptors = append(f.operationInterceptors[:len(f.operationInterceptors):len(f.operationInterceptors)], interceptors...)
+}
+
+// build the chain of interceptors around the HTTP client for an attempt
+func (f Freckle) roundTrip(op Operation) RoundTrip {
+	return chain(f.interceptors, op, f.client.Do)
+}
+
+// build a chain of interceptors around a round trip, the first
+// interceptor being the outermost one
+func chain(interceptors []Interceptor, op Operation, rt RoundTrip) RoundTrip {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], rt
+		rt = func(req *http.Request) (*http.Response, error) {
+			return interceptor(op, req, next)
+		}
+	}
+	return rt
+}
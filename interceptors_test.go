@@ -0,0 +1,146 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptorSeesOperation(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		}
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	var ops []string
+	var statuses []int
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		ops = append(ops, op.String())
+		resp, err := next(req)
+		if err == nil {
+			statuses = append(statuses, resp.StatusCode)
+		}
+		return resp, err
+	})
+
+	page, err := f.EntriesAPI().ListEntries()
+	assert.Nil(t, err, "Error should be nil")
+	_, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+
+	assert.Equal(t, []string{"EntriesAPI.ListEntries", "EntriesPage.Next"}, ops)
+	assert.Equal(t, []int{200, 200}, statuses)
+}
+
+func TestInterceptorsOrderAndHeaders(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects/37396", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "first,second", r.Header.Get("X-Trace"), "Interceptors should run in order")
+		response(single_project)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	header := func(value string) Interceptor {
+		return func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+			if trace := req.Header.Get("X-Trace"); trace != "" {
+				value = trace + "," + value
+			}
+			req.Header.Set("X-Trace", value)
+			return next(req)
+		}
+	}
+	f.Intercept(header("first"))
+	f.Intercept(header("second"))
+
+	_, err := f.ProjectsAPI().GetProject(37396)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestInterceptorCanAbortRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Request should not have reached the server")
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	blocked := errors.New("deleting is not allowed")
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		if op.Method == "DeleteProject" {
+			return nil, blocked
+		}
+		return next(req)
+	})
+
+	err := f.ProjectsAPI().DeleteProject(1234)
+	assert.ErrorIs(t, err, blocked)
+}
+
+func TestInterceptorSeesQueryOperations(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects", response(array_of_projects)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	var ops []string
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		ops = append(ops, op.String())
+		return next(req)
+	})
+
+	_, err := f.ProjectsAPI().ListProjects()
+	assert.Nil(t, err, "Error should be nil")
+	_, err = f.ProjectsAPI().QueryProjects(NewProjectsQuery().Billable(true))
+	assert.Nil(t, err, "Error should be nil")
+	_, err = f.ProjectsAPI().ListAllProjects(nil, 10)
+	assert.Nil(t, err, "Error should be nil")
+
+	assert.Equal(t, []string{"ProjectsAPI.ListProjects", "ProjectsAPI.QueryProjects", "ProjectsAPI.ListAllProjects"}, ops)
+}
+
+func TestOperationInterceptorAroundRetries(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts += 1
+		if attempts == 1 {
+			w.WriteHeader(503)
+			return
+		}
+		response(single_entry)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckleWithRetries(ts)
+
+	var calls []Operation
+	var statuses []int
+	f.InterceptOperations(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		calls = append(calls, op)
+		resp, err := next(req)
+		if err == nil {
+			statuses = append(statuses, resp.StatusCode)
+		}
+		return resp, err
+	})
+
+	_, err := f.EntriesAPI().GetEntry(1)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 2, attempts, "Should have retried once")
+	assert.Equal(t, []Operation{{Resource: "EntriesAPI", Method: "GetEntry"}}, calls, "Should have seen the operation once")
+	assert.Equal(t, []int{200}, statuses, "Should have seen the response of the last attempt")
+}
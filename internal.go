@@ -6,39 +6,49 @@ package freckle
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 )
 
 type onResponse func([]byte, *http.Response) error
 
-func (f Freckle) doHttpRequest(req *http.Request, fn onResponse) error {
-	f.log("Request: HTTP %s %s", req.Method, req.URL)
-
+// Send the HTTP request for an API operation, retrying it if needed
+func (f Freckle) doOperation(op Operation, req *http.Request, fn onResponse) error {
 	req.Header.Add("User-Agent", f.subdomain)
 	req.Header.Add("X-FreckleToken", f.key)
 
-	resp, err := f.client.Do(req)
-	if err != nil {
-		return err
+	var data []byte
+	attempts := func(req *http.Request) (*http.Response, error) {
+		for attempt := 1; ; attempt++ {
+			op := op
+			op.Attempt = attempt
+			var resp *http.Response
+			var err error
+			data, resp, err = f.send(f.roundTrip(op), req)
+			if f.retry.shouldRetry(attempt, req, resp, err) {
+				if err := f.retry.wait(req.Context(), attempt); err != nil {
+					return nil, err
+				}
+				if err := rewind(req); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return resp, err
+		}
 	}
-	defer resp.Body.Close()
 
-	data, err := body(resp)
+	resp, err := chain(f.operationInterceptors, op, attempts)(req)
 	if err != nil {
 		return err
 	}
 
-	f.log("Response: HTTP " + resp.Status)
-	for key, value := range resp.Header {
-		f.log("   %s: %s", key, value)
-	}
-	f.log("   %s", data)
-
 	if resp.StatusCode >= 400 {
 		return parseError(data, resp)
 	}
@@ -46,8 +56,45 @@ func (f Freckle) doHttpRequest(req *http.Request, fn onResponse) error {
 	return fn(data, resp)
 }
 
-//
-func (f Freckle) do(method, uri string, ps Parameters, is Inputs, fn onResponse) error {
+// Send the HTTP request once and read the response body
+func (f Freckle) send(rt RoundTrip, req *http.Request) ([]byte, *http.Response, error) {
+	if err := f.limiter.wait(req.Context()); err != nil {
+		return nil, nil, err
+	}
+
+	if f.loggingBodies() {
+		f.logHeadersAndBody(req.Context(), "freckle: request", req.Header, requestBody(req))
+	}
+
+	start := time.Now()
+	resp, err := rt(req)
+	if err != nil {
+		f.logRequest(req, nil, time.Since(start), err)
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	f.limiter.observe(resp)
+
+	data, err := body(resp)
+	if err != nil {
+		f.logRequest(req, resp, time.Since(start), err)
+		return nil, nil, err
+	}
+
+	f.logRequest(req, resp, time.Since(start), nil)
+	f.logHeadersAndBody(req.Context(), "freckle: response", resp.Header, data)
+
+	return data, resp, nil
+}
+
+// Build and send the HTTP request for an API operation
+func (f Freckle) do(op Operation, method, uri string, ps Parameters, is Inputs, fn onResponse) error {
+	return f.doContext(f.context(), op, method, uri, ps, is, fn)
+}
+
+// Build and send the HTTP request for an API operation, using the context provided
+func (f Freckle) doContext(ctx context.Context, op Operation, method, uri string, ps Parameters, is Inputs, fn onResponse) error {
 	u := f.api(uri, ps)
 
 	var b io.Reader
@@ -57,26 +104,30 @@ func (f Freckle) do(method, uri string, ps Parameters, is Inputs, fn onResponse)
 			return err
 		}
 		b = bytes.NewReader(data)
-		f.log("    %s", data)
 	}
 
-	req, err := http.NewRequest(method, u, b)
+	req, err := http.NewRequestWithContext(ctx, method, u, b)
 	if err != nil {
 		return err
 	}
 
-	return f.doHttpRequest(req, fn)
+	return f.doOperation(op, req, fn)
 }
 
-// Try to parse the data into a FreckleError object
+// Try to parse the data into a FreckleError object, falling back
+// to the HTTP status if the body doesn't contain the error details
 func parseError(data []byte, resp *http.Response) error {
 	var result FreckleError
-	err := json.Unmarshal(data, &result)
-	if err == nil {
-		return result
-	} else {
-		return err
+	if err := json.Unmarshal(data, &result); err != nil || result.Message == "" {
+		result.Message = http.StatusText(resp.StatusCode)
 	}
+	result.StatusCode = resp.StatusCode
+	result.Body = data
+	if resp.Request != nil {
+		result.Method = resp.Request.Method
+		result.URL = resp.Request.URL.String()
+	}
+	return result
 }
 
 // Apply a slice of ParameterSetter functions to create a Parameters instance
@@ -110,6 +161,19 @@ func (f Freckle) api(path string, ps Parameters) string {
 	return u
 }
 
+// Reset the request body so the request can be sent again
+func rewind(req *http.Request) error {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	b, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = b
+	return nil
+}
+
 // Extract body from the HTTP response
 func body(resp *http.Response) ([]byte, error) {
 	var buf bytes.Buffer
@@ -120,9 +184,77 @@ func body(resp *http.Response) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// Simple internal logging method
-func (f Freckle) log(msg string, data ...interface{}) {
-	if f.debug {
-		log.Printf("DEBUG: %s", fmt.Sprintf(msg, data...))
+// Get the context for API calls, defaulting to context.Background()
+func (f Freckle) context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
+}
+
+// Log the outcome of an HTTP request
+func (f Freckle) logRequest(req *http.Request, resp *http.Response, duration time.Duration, err error) {
+	if f.logger == nil {
+		return
+	}
+
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("duration", duration),
+	}
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", req.URL.RawQuery))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+	}
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", err.Error()))
 	}
+	f.logger.LogAttrs(req.Context(), level, "freckle: HTTP request", attrs...)
+}
+
+// Log headers and body, if enabled, with the API token redacted
+func (f Freckle) logHeadersAndBody(ctx context.Context, msg string, header http.Header, data []byte) {
+	if !f.loggingBodies() {
+		return
+	}
+
+	header = header.Clone()
+	if header.Get("X-FreckleToken") != "" {
+		header.Set("X-FreckleToken", "REDACTED")
+	}
+	attrs := make([]any, 0, len(header))
+	for key, values := range header {
+		attrs = append(attrs, slog.Any(key, values))
+	}
+	f.logger.LogAttrs(ctx, slog.LevelDebug, msg,
+		slog.Group("headers", attrs...),
+		slog.String("body", string(data)))
+}
+
+// Check if headers and bodies are being logged
+func (f Freckle) loggingBodies() bool {
+	return f.logger != nil && f.logBodies
+}
+
+// Get a copy of the request body for logging
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	b, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer b.Close()
+
+	data, _ := io.ReadAll(b)
+	return data
 }
@@ -0,0 +1,112 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const InvoiceStateAwaitingPayment = "awaiting_payment"
+const InvoiceStateUnpaid = "unpaid"
+const InvoiceStatePaid = "paid"
+
+type InvoicesAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (i InvoicesAPI) op(method string) Operation {
+	return Operation{Resource: "InvoicesAPI", Method: method}
+}
+
+func (i InvoicesAPI) ListInvoices(fns ...ParameterSetter) (InvoicesPage, error) {
+	result := emptyInvoicesPage(i.freckle)
+	return result, i.freckle.do(i.op("ListInvoices"), "GET", "/invoices", parameters(fns), nil, result.onResponse)
+}
+
+func emptyInvoicesPage(f *Freckle) InvoicesPage {
+	return emptyPage[Invoice](f, "InvoicesPage")
+}
+
+func (i InvoicesAPI) GetInvoice(id int) (Invoice, error) {
+	var result Invoice
+	return result, i.freckle.do(i.op("GetInvoice"), "GET", fmt.Sprintf("/invoices/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (i InvoicesAPI) CreateInvoice(fns ...InputSetter) (Invoice, error) {
+	var result Invoice
+	return result, i.freckle.do(i.op("CreateInvoice"), "POST", "/invoices", nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (i InvoicesAPI) EditInvoice(id int, fns ...InputSetter) (Invoice, error) {
+	var result Invoice
+	return result, i.freckle.do(i.op("EditInvoice"), "PUT", fmt.Sprintf("/invoices/%d", id), nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+// Change the state of an invoice (e.g. InvoiceStatePaid)
+func (i InvoicesAPI) ChangeState(id int, state string) error {
+	is := make(Inputs)
+	is["state"] = state
+
+	return i.freckle.do(i.op("ChangeState"), "PUT", fmt.Sprintf("/invoices/%d/change_state", id), nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (i InvoicesAPI) DeleteInvoice(id int) error {
+	return i.freckle.do(i.op("DeleteInvoice"), "DELETE", fmt.Sprintf("/invoices/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (i InvoicesAPI) GetEntries(id int) (EntriesPage, error) {
+	result := emptyEntriesPage(i.freckle)
+	return result, i.freckle.do(i.op("GetEntries"), "GET", fmt.Sprintf("/invoices/%d/entries", id), nil, nil, result.onResponse)
+}
+
+func (i InvoicesAPI) GetExpenses(id int) (ExpensesPage, error) {
+	result := emptyExpensesPage(i.freckle)
+	return result, i.freckle.do(i.op("GetExpenses"), "GET", fmt.Sprintf("/invoices/%d/expenses", id), nil, nil, result.onResponse)
+}
+
+// The API uses different field names for the invoice reference and total
+// depending on where the invoice shows up, so accept all of them here.
+func (i *Invoice) UnmarshalJSON(data []byte) error {
+	type invoice Invoice
+	aux := struct {
+		*invoice
+		Number      string  `json:"number"`
+		Total       float64 `json:"total"`
+		AmountTotal float64 `json:"amount_total"`
+	}{invoice: (*invoice)(i)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if i.Reference == "" {
+		i.Reference = aux.Number
+	}
+	if i.TotalAmount == 0 {
+		i.TotalAmount = aux.AmountTotal
+	}
+	if i.TotalAmount == 0 {
+		i.TotalAmount = aux.Total
+	}
+	return nil
+}
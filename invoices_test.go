@@ -0,0 +1,184 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListInvoices(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/invoices", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "unpaid", r.URL.Query().Get("state"))
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2&state=unpaid>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(invoices_for_project)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.InvoicesAPI().ListInvoices(func(p Parameters) {
+		p["state"] = InvoiceStateUnpaid
+	})
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one invoice")
+	invoice := page.Items[0]
+	assert.Equal(t, "AB 0001", invoice.Reference, "Invoice reference mismatch")
+	assert.Equal(t, 1.0, invoice.TotalAmount, "Invoice total mismatch")
+	assert.Equal(t, 1, len(invoice.Taxes), "Should have one tax")
+	assert.Equal(t, 1, len(invoice.Projects), "Should have one project")
+	assert.True(t, page.HasNext(), "Should have a next page")
+
+	page, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one invoice")
+}
+
+func TestGetInvoice(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/invoices/26642", response(single_invoice)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	invoice, err := f.InvoicesAPI().GetInvoice(26642)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, InvoiceStateAwaitingPayment, invoice.State, "Invoice state mismatch")
+}
+
+func TestCreateInvoice(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/invoices", response(single_invoice)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.InvoicesAPI().CreateInvoice(func(i Inputs) {
+		i["invoice_date"] = "2013-07-09"
+		i["project_ids"] = []int{37396}
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestEditInvoice(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/invoices/26642", response(single_invoice)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.InvoicesAPI().EditInvoice(26642, func(i Inputs) {
+		i["footer"] = "Thank you!"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestChangeInvoiceState(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/invoices/26642/change_state", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body), "Body should be valid JSON")
+		assert.Equal(t, InvoiceStatePaid, body["state"])
+		noContent()(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.InvoicesAPI().ChangeState(26642, InvoiceStatePaid)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeleteInvoice(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/invoices/26642", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.InvoicesAPI().DeleteInvoice(26642)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestGetInvoiceEntries(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/invoices/26642/entries", response(array_of_entries)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.InvoicesAPI().GetEntries(26642)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
+}
+
+func TestGetInvoiceExpenses(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/invoices/26642/expenses", response(array_of_expenses)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.InvoicesAPI().GetExpenses(26642)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one expense")
+}
+
+func TestInvoiceFieldNames(t *testing.T) {
+	var entry Entry
+	assert.Nil(t, json.Unmarshal([]byte(single_entry), &entry), "Error should be nil")
+	assert.Equal(t, "AA001", entry.Invoice.Reference, "number should map onto Reference")
+	assert.Equal(t, 189.33, entry.Invoice.TotalAmount, "total should map onto TotalAmount")
+
+	var project Project
+	assert.Nil(t, json.Unmarshal([]byte(single_project), &project), "Error should be nil")
+	assert.Equal(t, "AA001", project.Invoices[0].Reference, "reference mismatch")
+	assert.Equal(t, 189.33, project.Invoices[0].TotalAmount, "total_amount mismatch")
+}
+
+const single_invoice = `{
+  "id": 26642,
+  "state": "awaiting_payment",
+  "number": "AB 0001",
+  "invoice_date": "2013-07-09",
+  "name": "Knockd, Freckle Support",
+  "company_name": "John Test",
+  "company_details": "1 Main Street\\r\\nMainsville, MA 11122",
+  "recipient_details": "",
+  "description": "",
+  "footer": "",
+  "show_hours": true,
+  "show_details": false,
+  "show_summaries": false,
+  "taxes": [
+    {
+      "id": 88292,
+      "name": "Sales Tax",
+      "percentage": 15.0
+    }
+  ],
+  "amount_taxable": 100,
+  "amount_taxfree": 0,
+  "amount_tax_total": 0,
+  "amount_total": 1,
+  "amount_total_with_currency": "$1.00",
+  "share_url": "https://apitest.letsfreckle.com/i/bqrnbojlbxqswtq9xla9uc40z",
+  "projects": [
+    {
+      "id": 37396,
+      "name": "Gear GmbH",
+      "billing_increment": 10,
+      "enabled": true,
+      "billable": true,
+      "color": "#ff9898",
+      "url": "https://api.letsfreckle.com/v2/projects/37396"
+    }
+  ],
+  "entries": 0,
+  "entries_url": "https://api.letsfreckle.com/v2/invoices/26642/entries",
+  "expenses": 0,
+  "expenses_url": "https://api.letsfreckle.com/v2/invoices/26642/expenses",
+  "created_at": "2013-07-09T23:04:05Z",
+  "updated_at": "2013-07-09T23:04:06Z"
+}`
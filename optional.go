@@ -0,0 +1,82 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+)
+
+// Value that can be missing, explicitly null or set. Unlike a plain field
+// with omitempty, false and 0 are kept when marshalling an Optional that has
+// been set, while a missing value is left out with omitzero.
+type Optional[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Get an Optional that has been set to a value
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{value: value, set: true}
+}
+
+// Get an Optional that has explicitly been set to null, e.g. to clear
+// the budget of a project
+func Null[T any]() Optional[T] {
+	return Optional[T]{null: true}
+}
+
+// Get the value and whether it has been set
+func (o Optional[T]) Get() (T, bool) {
+	return o.value, o.set
+}
+
+// Get the value, or the zero value if it is missing or null
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// Check if the value has been set
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// Check if the value has explicitly been set to null
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// Check if the value is missing, so omitzero leaves it out
+func (o Optional[T]) IsZero() bool {
+	return !o.set && !o.null
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*o = Null[T]()
+		return nil
+	}
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*o = Some(value)
+	return nil
+}
+
+// get the value to send as input, nil for null
+func (o Optional[T]) input() interface{} {
+	if o.null {
+		return nil
+	}
+	return o.value
+}
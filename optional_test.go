@@ -0,0 +1,40 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptional(t *testing.T) {
+	value, ok := Some(false).Get()
+	assert.False(t, value)
+	assert.True(t, ok, "Value should be set")
+
+	_, ok = Null[int]().Get()
+	assert.False(t, ok, "Null should not be set")
+	assert.True(t, Null[int]().IsNull(), "Null should be null")
+	assert.True(t, Optional[int]{}.IsZero(), "Missing value should be zero")
+	assert.False(t, Null[int]().IsZero(), "Null should not be zero")
+}
+
+func TestOptionalJSON(t *testing.T) {
+	var project Project
+	assert.Nil(t, json.Unmarshal([]byte(`{"enabled": false, "budget_minutes": null}`), &project))
+	assert.Equal(t, Some(false), project.Enabled, "False should be kept")
+	assert.Equal(t, Optional[bool]{}, project.Billable, "Missing value should not be set")
+	assert.True(t, project.BudgetMinutes.IsNull(), "Null should be kept")
+
+	data, err := json.Marshal(Project{Billable: Some(false), BudgetMinutes: Null[int]()})
+	assert.Nil(t, err, "Error should be nil")
+	assert.JSONEq(t, `{"billable": false, "budget_minutes": null}`, string(data))
+
+	data, err = json.Marshal(Tag{Name: "freckle"})
+	assert.Nil(t, err, "Error should be nil")
+	assert.JSONEq(t, `{"name": "freckle"}`, string(data), "Missing values should be left out")
+}
@@ -0,0 +1,145 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This package adds OpenTelemetry tracing and metrics to the Freckle API client.
+//
+// Every API call gets a single client span named after the operation (e.g.
+// EntriesAPI.ListEntries), carrying the status code, the page number when a
+// specific page is requested and the number of retries, with a retry event
+// for every attempt after the first.
+// The latency of the calls, including any retries, is recorded in the
+// freckle.client.duration histogram and failed calls are counted in the
+// freckle.client.errors counter.
+package otelfreckle
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gertv/go-freckle"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/gertv/go-freckle/otelfreckle"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Function to configure the instrumentation
+type Option func(*config)
+
+// Use the tracer provider given instead of the global one
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// Use the meter provider given instead of the global one
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Add tracing and metrics to all API calls made with the Freckle client
+func Instrument(f *freckle.Freckle, opts ...Option) error {
+	operation, attempt, err := Interceptors(opts...)
+	if err != nil {
+		return err
+	}
+	f.InterceptOperations(operation)
+	f.Intercept(attempt)
+	return nil
+}
+
+// Get the interceptors that add tracing and metrics to the API calls. The
+// operation interceptor has to be added with Freckle.InterceptOperations and
+// creates the span and records the metrics for every call, the attempt
+// interceptor has to be added with Freckle.Intercept and records the retries.
+func Interceptors(opts ...Option) (operation, attempt freckle.Interceptor, err error) {
+	c := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	tracer := c.tracerProvider.Tracer(instrumentationName)
+	meter := c.meterProvider.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram("freckle.client.duration",
+		metric.WithDescription("Duration of the calls to the Freckle API, including retries"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, nil, err
+	}
+	failures, err := meter.Int64Counter("freckle.client.errors",
+		metric.WithDescription("Number of failed calls to the Freckle API"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	operation = func(op freckle.Operation, req *http.Request, next freckle.RoundTrip) (*http.Response, error) {
+		name := attribute.String("freckle.operation", op.String())
+		ctx, span := tracer.Start(req.Context(), op.String(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				name,
+				attribute.String("http.request.method", req.Method),
+				attribute.Int("freckle.retries", 0)))
+		defer span.End()
+		if p, ok := page(req); ok {
+			span.SetAttributes(attribute.Int("freckle.page", p))
+		}
+
+		start := time.Now()
+		resp, err := next(req.WithContext(ctx))
+		elapsed := time.Since(start).Seconds()
+
+		attrs := []attribute.KeyValue{name}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			status := attribute.Int("http.response.status_code", resp.StatusCode)
+			span.SetAttributes(status)
+			attrs = append(attrs, status)
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+			}
+		}
+
+		duration.Record(ctx, elapsed, metric.WithAttributes(attrs...))
+		if err != nil || resp.StatusCode >= 400 {
+			failures.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+		return resp, err
+	}
+
+	attempt = func(op freckle.Operation, req *http.Request, next freckle.RoundTrip) (*http.Response, error) {
+		if op.Attempt > 1 {
+			span := trace.SpanFromContext(req.Context())
+			span.SetAttributes(attribute.Int("freckle.retries", op.Attempt-1))
+			span.AddEvent("retry", trace.WithAttributes(attribute.Int("freckle.attempt", op.Attempt)))
+		}
+		return next(req)
+	}
+
+	return operation, attempt, nil
+}
+
+// get the page number requested, if the request asks for a specific page
+func page(req *http.Request) (int, bool) {
+	p, err := strconv.Atoi(req.URL.Query().Get("page"))
+	return p, err == nil
+}
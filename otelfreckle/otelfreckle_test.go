@@ -0,0 +1,166 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package otelfreckle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gertv/go-freckle"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// HTTP transport that sends all requests to the test server instead
+type testServer struct {
+	url *url.URL
+}
+
+func (s testServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme = s.url.Scheme
+	req.URL.Host = s.url.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func instrumentedFreckle(t *testing.T, ts *httptest.Server) (freckle.Freckle, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	u, _ := url.Parse(ts.URL)
+	f := freckle.LetsFreckle("mydomain", "abcdefghijklmnopqrstuvwxyz")
+	f.Client(&http.Client{Transport: testServer{u}})
+
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	err := Instrument(&f,
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	assert.Nil(t, err, "Error should be nil")
+
+	return f, exporter, reader
+}
+
+func attributes(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	result := make(map[attribute.Key]attribute.Value)
+	for _, kv := range kvs {
+		result[kv.Key] = kv.Value
+	}
+	return result
+}
+
+func TestSpans(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		}
+		fmt.Fprintln(w, `[{"id": 1}]`)
+	}))
+	defer ts.Close()
+
+	f, exporter, _ := instrumentedFreckle(t, ts)
+
+	page, err := f.EntriesAPI().ListEntries()
+	assert.Nil(t, err, "Error should be nil")
+	_, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 2, len(spans), "Should have created a span for every call")
+	assert.Equal(t, "EntriesAPI.ListEntries", spans[0].Name, "Span name mismatch")
+	assert.Equal(t, "EntriesPage.Next", spans[1].Name, "Span name mismatch")
+
+	_, ok := attributes(spans[0].Attributes)["freckle.page"]
+	assert.False(t, ok, "Should not guess the page number when no page is requested")
+
+	attrs := attributes(spans[1].Attributes)
+	assert.Equal(t, int64(2), attrs["freckle.page"].AsInt64(), "Page number mismatch")
+	assert.Equal(t, int64(0), attrs["freckle.retries"].AsInt64(), "Retries mismatch")
+	assert.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64(), "Status code mismatch")
+}
+
+func TestMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+		fmt.Fprintln(w, `{"message": "Not Found"}`)
+	}))
+	defer ts.Close()
+
+	f, exporter, reader := instrumentedFreckle(t, ts)
+
+	_, err := f.ProjectsAPI().GetProject(1234)
+	assert.True(t, freckle.IsNotFound(err), "Should be a not found error")
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 1, len(spans), "Should have created one span")
+	assert.Equal(t, codes.Error, spans[0].Status.Code, "Span should have an error status")
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.Background(), &rm), "Error should be nil")
+	assert.Equal(t, 1, len(rm.ScopeMetrics), "Should have metrics for one scope")
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	histogram, ok := metrics["freckle.client.duration"].(metricdata.Histogram[float64])
+	assert.True(t, ok, "Should have recorded the duration histogram")
+	assert.Equal(t, uint64(1), histogram.DataPoints[0].Count, "Should have recorded one request")
+
+	counter, ok := metrics["freckle.client.errors"].(metricdata.Sum[int64])
+	assert.True(t, ok, "Should have counted the errors")
+	assert.Equal(t, int64(1), counter.DataPoints[0].Value, "Should have counted one error")
+	operation, _ := counter.DataPoints[0].Attributes.Value("freckle.operation")
+	assert.Equal(t, "ProjectsAPI.GetProject", operation.AsString(), "Operation mismatch")
+}
+
+func TestOneSpanForRetriedCall(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		fmt.Fprintln(w, `{"id": 1}`)
+	}))
+	defer ts.Close()
+
+	f, exporter, reader := instrumentedFreckle(t, ts)
+	policy := freckle.DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	f.Retry(policy)
+
+	entry, err := f.EntriesAPI().GetEntry(1)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, entry.Id, "Entry id mismatch")
+	assert.Equal(t, 3, attempts, "Should have retried twice")
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, 1, len(spans), "Should have created one span for the call")
+	assert.Equal(t, "EntriesAPI.GetEntry", spans[0].Name, "Span name mismatch")
+	assert.Equal(t, codes.Unset, spans[0].Status.Code, "Span should not have an error status")
+	attrs := attributes(spans[0].Attributes)
+	assert.Equal(t, int64(2), attrs["freckle.retries"].AsInt64(), "Retries mismatch")
+	assert.Equal(t, int64(200), attrs["http.response.status_code"].AsInt64(), "Status code mismatch")
+	assert.Equal(t, 2, len(spans[0].Events), "Should have an event for every retry")
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.Background(), &rm), "Error should be nil")
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if histogram, ok := m.Data.(metricdata.Histogram[float64]); ok {
+			assert.Equal(t, uint64(1), histogram.DataPoints[0].Count, "Should have recorded the call once")
+		}
+		assert.NotEqual(t, "freckle.client.errors", m.Name, "Should not have counted an error")
+	}
+}
@@ -5,8 +5,15 @@
 package freckle
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"iter"
 	"net/http"
-	"regexp"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 const FirstPage = "first"
@@ -14,70 +21,148 @@ const LastPage = "last"
 const NextPage = "next"
 const PreviousPage = "prev"
 
-// Is there a next page of entries?
-func (p EntriesPage) HasNext() bool {
-	return p.has(NextPage)
+// Page of items returned by one of the list calls, with the links to the
+// other pages of the listing. EntriesPage and ProjectsPage embed a Page, so
+// all of its methods are available on them as well, the other page types
+// such as TagsPage are the same type as the Page of their items.
+type Page[T any] struct {
+	links    map[string]string
+	freckle  *Freckle
+	resource string
+	number   int
+	perPage  int
+	Items    []T
 }
 
-// Get the next page of entries
-func (p EntriesPage) Next() (EntriesPage, error) {
-	return p.fetch(NextPage)
+// Get a ParameterSetter for the number of items per page of a list call
+func PerPage(n int) ParameterSetter {
+	return func(p Parameters) {
+		if n > 0 {
+			p["per_page"] = strconv.Itoa(n)
+		}
+	}
 }
 
-// Is there a previous page of entries?
-func (p EntriesPage) HasPrevious() bool {
-	return p.has(PreviousPage)
+// get an empty page to fetch the first page of a listing into
+func emptyPage[T any](f *Freckle, resource string) Page[T] {
+	return Page[T]{freckle: f, resource: resource}
 }
 
-// Get the previous page of entries
-func (p EntriesPage) Previous() (EntriesPage, error) {
-	return p.fetch(PreviousPage)
+// store the items and the pagination links from the response
+func (p *Page[T]) onResponse(data []byte, resp *http.Response) error {
+	links := pagelinks(strings.Join(resp.Header.Values("Link"), ","))
+	var items []T
+
+	err := json.Unmarshal(data, &items)
+	p.links = links
+	p.Items = items
+	p.number, p.perPage = pageNumbers(resp.Request, links)
+	return err
 }
 
-// Get the first page of entries
-func (p EntriesPage) First() (EntriesPage, error) {
-	return p.fetch(FirstPage)
+// Get the number of this page, starting at 1
+func (p Page[T]) Number() int {
+	return p.number
 }
 
-// Get the last page of entries
-func (p EntriesPage) Last() (EntriesPage, error) {
-	return p.fetch(LastPage)
+// Get the number of items per page that was asked for, or 0 if the
+// list call left it up to the API
+func (p Page[T]) PerPage() int {
+	return p.perPage
+}
+
+// Get the total number of pages, if it is known from the link to the
+// last page or because this is the last page
+func (p Page[T]) TotalPages() (int, bool) {
+	if last, ok := linkPage(p.links[LastPage]); ok {
+		return last, true
+	}
+	if !p.HasNext() && p.number > 0 {
+		return p.number, true
+	}
+	return 0, false
+}
+
+// Get a copy of this page that uses the context provided for fetching other pages
+func (p Page[T]) WithContext(ctx context.Context) Page[T] {
+	f := p.freckle.WithContext(ctx)
+	p.freckle = &f
+	return p
 }
 
-// Is there a next page of projects?
-func (p ProjectsPage) HasNext() bool {
+// Is there a next page?
+func (p Page[T]) HasNext() bool {
 	return p.has(NextPage)
 }
 
-// Get the next page of projects
-func (p ProjectsPage) Next() (ProjectsPage, error) {
-	return p.fetch(NextPage)
+// Get the next page
+func (p Page[T]) Next() (Page[T], error) {
+	return p.fetch(p.freckle.context(), NextPage, "Next")
 }
 
-// Is there a previous page of projects?
-func (p ProjectsPage) HasPrevious() bool {
+// Get the next page, using the context provided for this request only.
+// Unlike with WithContext, the page returned does not keep the context.
+func (p Page[T]) NextContext(ctx context.Context) (Page[T], error) {
+	return p.fetch(ctx, NextPage, "Next")
+}
+
+// Is there a previous page?
+func (p Page[T]) HasPrevious() bool {
 	return p.has(PreviousPage)
 }
 
-// Get the previous page of projects
-func (p ProjectsPage) Previous() (ProjectsPage, error) {
-	return p.fetch(PreviousPage)
+// Get the previous page
+func (p Page[T]) Previous() (Page[T], error) {
+	return p.fetch(p.freckle.context(), PreviousPage, "Previous")
 }
 
-// Get the first page of projects
-func (p ProjectsPage) First() (ProjectsPage, error) {
-	return p.fetch(FirstPage)
+// Get the first page
+func (p Page[T]) First() (Page[T], error) {
+	return p.fetch(p.freckle.context(), FirstPage, "First")
 }
 
-// Get the last page of projects
-func (p ProjectsPage) Last() (ProjectsPage, error) {
-	return p.fetch(LastPage)
+// Get the last page
+func (p Page[T]) Last() (Page[T], error) {
+	return p.fetch(p.freckle.context(), LastPage, "Last")
+}
+
+// Get an iterator over all items on this page and the subsequent ones.
+// Unlike AllItems, a failure to fetch the next page is yielded as an error
+// after which the iteration stops.
+func (p Page[T]) All() iter.Seq2[T, error] {
+	return p.all(p.freckle.context())
+}
+
+// iterate over all items, fetching the next pages with a context
+func (p Page[T]) all(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		page := p
+		for {
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasNext() {
+				return
+			}
+			next, err := page.fetch(ctx, NextPage, "Next")
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			page = next
+		}
+	}
 }
 
-// Get a channel to receive all entries. After all entries from the current
+// Get a channel to receive all items. After all items from the current
 // page have been received, the next page will automatically be fetched.
-func (p EntriesPage) AllEntries() chan Entry {
-	result := make(chan Entry)
+// The channel is also closed when fetching a page fails, use All() instead
+// if you need to know about that error.
+func (p Page[T]) AllItems() chan T {
+	result := make(chan T)
 	go func() {
 		p.push(result)
 		close(result)
@@ -85,89 +170,545 @@ func (p EntriesPage) AllEntries() chan Entry {
 	return result
 }
 
-// Get a channel to receive all projects. Ater all projects from the current
-// page have been receive, the next page will automatically be fetched.
-func (p ProjectsPage) AllProjects() chan Project {
-	result := make(chan Project)
-	go func() {
-		p.push(result)
-		close(result)
-	}()
-	return result
+// Get a channel to receive all items like AllItems, together with a
+// function to stop receiving them. Call stop when you're no longer reading
+// from the channel (e.g. after breaking out of the loop early) to release
+// the goroutine that is fetching the pages.
+func (p Page[T]) StreamItems() (<-chan T, func()) {
+	ctx, stop := context.WithCancel(p.freckle.context())
+	return p.WithContext(ctx).AllItems(), stop
 }
 
-// push all entries for current page and the next ones to the channel provided
-func (p EntriesPage) push(c chan Entry) {
-	for _, e := range p.Entries {
-		c <- e
+// Get an iterator over all items like All, but fetch up to the number of
+// pages given ahead in the background instead of waiting for all items on
+// the current page to be consumed first.
+func (p Page[T]) Prefetch(pages int) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(p.freckle.context())
+		defer cancel()
+
+		// set before the results are closed when all pages have been fetched,
+		// so a listing cut short by the context is not mistaken for a full one
+		complete := false
+		results := make(chan pageResult[T], max(pages, 1)-1)
+		go func() {
+			defer close(results)
+			page := p
+			for page.HasNext() {
+				next, err := page.fetch(ctx, NextPage, "Next")
+				select {
+				case results <- pageResult[T]{next, err}:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+				page = next
+			}
+			complete = true
+		}()
+
+		if !yieldAll(yield, p.Items) {
+			return
+		}
+		for result := range results {
+			if !yieldResult(yield, result) {
+				return
+			}
+		}
+		if !complete {
+			var zero T
+			yield(zero, ctx.Err())
+		}
 	}
-	if p.HasNext() {
-		next, err := p.Next()
-		if err == nil {
-			next.push(c)
+}
+
+// Get an iterator over all items like All, but fetch the subsequent pages
+// in parallel, using at most the number of workers given. The items are
+// still yielded in order. This requires the number of the last page, so it
+// falls back to Prefetch if the API did not provide a link to the last page.
+func (p Page[T]) Parallel(workers int) iter.Seq2[T, error] {
+	urls, ok := p.remaining()
+	if !ok {
+		return p.Prefetch(workers)
+	}
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(p.freckle.context())
+		defer cancel()
+
+		slots := make(chan struct{}, max(workers, 1))
+		results := make([]chan pageResult[T], len(urls))
+		for i := range results {
+			results[i] = make(chan pageResult[T], 1)
+		}
+		// a slot is taken for every page being fetched and only released
+		// when that page has been consumed, so at most workers pages are
+		// kept in memory at any time
+		go func() {
+			for i, u := range urls {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					next, err := p.fetchURL(ctx, u, "Next")
+					results[i] <- pageResult[T]{next, err}
+				}()
+			}
+		}()
+
+		if !yieldAll(yield, p.Items) {
+			return
+		}
+		for _, c := range results {
+			var result pageResult[T]
+			select {
+			case result = <-c:
+			case <-ctx.Done():
+				var zero T
+				yield(zero, ctx.Err())
+				return
+			}
+			if !yieldResult(yield, result) {
+				return
+			}
+			<-slots
+		}
+	}
+}
+
+// Maximum number of items collected when no maximum is given
+const DefaultMaxItems = 10000
+
+// Collect the items on this page and the subsequent ones into a slice.
+// Collecting stops with an error wrapping ErrTooManyItems as soon as there
+// are more than maxItems items, or DefaultMaxItems if maxItems is not
+// positive, so a runaway listing can not exhaust memory.
+func (p Page[T]) Collect(maxItems int) ([]T, error) {
+	return p.collect(p.freckle.context(), maxItems)
+}
+
+// collect the items, fetching the next pages with a context
+func (p Page[T]) collect(ctx context.Context, maxItems int) ([]T, error) {
+	if maxItems <= 0 {
+		maxItems = DefaultMaxItems
+	}
+	tooMany := fmt.Errorf("%w: listing has more than %d items", ErrTooManyItems, maxItems)
+
+	// all pages before the last one are as full as this one, whatever page
+	// size was asked for, so fail early if the remaining pages are known to
+	// push the listing over the maximum
+	if total, ok := p.TotalPages(); ok && p.HasNext() && total > p.number {
+		if (total-p.number)*len(p.Items)+1 > maxItems {
+			return nil, tooMany
+		}
+	}
+
+	var result []T
+	for item, err := range p.all(ctx) {
+		if err != nil {
+			return nil, err
+		}
+		if len(result) == maxItems {
+			return nil, tooMany
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// outcome of fetching a page in the background
+type pageResult[T any] struct {
+	page Page[T]
+	err  error
+}
+
+// yield all items, reporting whether to continue
+func yieldAll[T any](yield func(T, error) bool, items []T) bool {
+	for _, item := range items {
+		if !yield(item, nil) {
+			return false
 		}
 	}
+	return true
+}
+
+// yield the items or the error of a page fetched in the background,
+// reporting whether to continue
+func yieldResult[T any](yield func(T, error) bool, result pageResult[T]) bool {
+	if result.err != nil {
+		var zero T
+		yield(zero, result.err)
+		return false
+	}
+	return yieldAll(yield, result.page.Items)
+}
+
+// get the URLs of all pages after this one from the number of the last page
+func (p Page[T]) remaining() ([]string, bool) {
+	if !p.HasNext() {
+		return nil, true
+	}
+	next, ok := linkPage(p.links[NextPage])
+	if !ok {
+		return nil, false
+	}
+	last, ok := linkPage(p.links[LastPage])
+	if !ok || last < next {
+		return nil, false
+	}
+	u, err := url.Parse(p.links[LastPage])
+	if err != nil {
+		return nil, false
+	}
+	var urls []string
+	for n := next; n <= last; n++ {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(n))
+		u.RawQuery = q.Encode()
+		urls = append(urls, u.String())
+	}
+	return urls, true
+}
+
+// get the page number from the page parameter of a pagination link
+func linkPage(link string) (int, bool) {
+	return linkParam(link, "page")
+}
+
+// get a positive number from a parameter of a pagination link
+func linkParam(link, name string) (int, bool) {
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(u.Query().Get(name))
+	return n, err == nil && n > 0
 }
 
-// push all projects for current page and the next ones to the channel provided
-func (p ProjectsPage) push(c chan Project) {
-	for _, p := range p.Projects {
-		c <- p
+// get the number and size of a page from its request, or from the
+// links to the other pages if the request did not specify them
+func pageNumbers(req *http.Request, links map[string]string) (int, int) {
+	var number, perPage int
+	if req != nil {
+		number, _ = linkParam(req.URL.String(), "page")
+		perPage, _ = linkParam(req.URL.String(), "per_page")
 	}
-	if p.HasNext() {
+	if number == 0 {
+		number = 1
+		if prev, ok := linkPage(links[PreviousPage]); ok {
+			number = prev + 1
+		} else if next, ok := linkPage(links[NextPage]); ok {
+			number = next - 1
+		}
+	}
+	if perPage == 0 {
+		for _, rel := range []string{NextPage, PreviousPage, FirstPage, LastPage} {
+			if n, ok := linkParam(links[rel], "per_page"); ok {
+				perPage = n
+				break
+			}
+		}
+	}
+	return number, perPage
+}
+
+// push all items for current page and the next ones to the channel provided
+func (p Page[T]) push(c chan T) {
+	for {
+		for _, item := range p.Items {
+			select {
+			case c <- item:
+			case <-p.freckle.context().Done():
+				return
+			}
+		}
+		if !p.HasNext() {
+			return
+		}
 		next, err := p.Next()
-		if err == nil {
-			next.push(c)
+		if err != nil {
+			return
 		}
+		p = next
 	}
 }
 
 // check if there is a page relative to the current one
-func (p EntriesPage) has(id string) bool {
+func (p Page[T]) has(id string) bool {
 	_, ok := p.links[id]
 	return ok
 }
 
-// check if there is a page relative to the current one
-func (p ProjectsPage) has(id string) bool {
-	_, ok := p.links[id]
-	return ok
+// fetch another page relative to the current one
+func (p Page[T]) fetch(ctx context.Context, id, method string) (Page[T], error) {
+	return p.fetchURL(ctx, p.links[id], method)
 }
 
-// fetch another entries page relative to the current one
-func (p EntriesPage) fetch(id string) (EntriesPage, error) {
+// fetch another page of the same listing by its URL
+func (p Page[T]) fetchURL(ctx context.Context, u, method string) (Page[T], error) {
 	f := p.freckle
-	result := EntriesPage{freckle: f}
+	result := emptyPage[T](f, p.resource)
 
-	req, err := http.NewRequest("GET", p.links[id], nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return result, err
 	}
 
-	return result, f.doHttpRequest(req, result.onResponse)
+	op := Operation{Resource: p.resource, Method: method}
+	return result, f.doOperation(op, req, result.onResponse)
 }
 
-// fetch another entries page relative to the current one
-func (p ProjectsPage) fetch(id string) (ProjectsPage, error) {
-	f := p.freckle
-	result := emptyProjectsPage(f)
+// Page of entries, see Page for navigating it
+type EntriesPage struct {
+	Page[Entry]
+	// The same entries as in Items
+	Entries []Entry
+}
 
-	req, err := http.NewRequest("GET", p.links[id], nil)
-	if err != nil {
-		return result, err
+func wrapEntriesPage(p Page[Entry], err error) (EntriesPage, error) {
+	return EntriesPage{Page: p, Entries: p.Items}, err
+}
+
+// Get a copy of this page that uses the context provided for fetching other pages
+func (p EntriesPage) WithContext(ctx context.Context) EntriesPage {
+	return EntriesPage{Page: p.Page.WithContext(ctx), Entries: p.Entries}
+}
+
+// Get the next page of entries
+func (p EntriesPage) Next() (EntriesPage, error) {
+	return wrapEntriesPage(p.Page.Next())
+}
+
+// Get the next page of entries, using the context provided for this request only
+func (p EntriesPage) NextContext(ctx context.Context) (EntriesPage, error) {
+	return wrapEntriesPage(p.Page.NextContext(ctx))
+}
+
+// Get the previous page of entries
+func (p EntriesPage) Previous() (EntriesPage, error) {
+	return wrapEntriesPage(p.Page.Previous())
+}
+
+// Get the first page of entries
+func (p EntriesPage) First() (EntriesPage, error) {
+	return wrapEntriesPage(p.Page.First())
+}
+
+// Get the last page of entries
+func (p EntriesPage) Last() (EntriesPage, error) {
+	return wrapEntriesPage(p.Page.Last())
+}
+
+// Get a channel to receive all entries, see AllItems
+func (p EntriesPage) AllEntries() chan Entry {
+	return p.AllItems()
+}
+
+// Get a channel to receive all entries and a function to stop, see StreamItems
+func (p EntriesPage) StreamEntries() (<-chan Entry, func()) {
+	return p.StreamItems()
+}
+
+// Page of projects, see Page for navigating it
+type ProjectsPage struct {
+	Page[Project]
+	// The same projects as in Items
+	Projects []Project
+}
+
+func wrapProjectsPage(p Page[Project], err error) (ProjectsPage, error) {
+	return ProjectsPage{Page: p, Projects: p.Items}, err
+}
+
+// Get a copy of this page that uses the context provided for fetching other pages
+func (p ProjectsPage) WithContext(ctx context.Context) ProjectsPage {
+	return ProjectsPage{Page: p.Page.WithContext(ctx), Projects: p.Projects}
+}
+
+// Get the next page of projects
+func (p ProjectsPage) Next() (ProjectsPage, error) {
+	return wrapProjectsPage(p.Page.Next())
+}
+
+// Get the next page of projects, using the context provided for this request only
+func (p ProjectsPage) NextContext(ctx context.Context) (ProjectsPage, error) {
+	return wrapProjectsPage(p.Page.NextContext(ctx))
+}
+
+// Get the previous page of projects
+func (p ProjectsPage) Previous() (ProjectsPage, error) {
+	return wrapProjectsPage(p.Page.Previous())
+}
+
+// Get the first page of projects
+func (p ProjectsPage) First() (ProjectsPage, error) {
+	return wrapProjectsPage(p.Page.First())
+}
+
+// Get the last page of projects
+func (p ProjectsPage) Last() (ProjectsPage, error) {
+	return wrapProjectsPage(p.Page.Last())
+}
+
+// Get a channel to receive all projects, see AllItems
+func (p ProjectsPage) AllProjects() chan Project {
+	return p.AllItems()
+}
+
+// Get a channel to receive all projects and a function to stop, see StreamItems
+func (p ProjectsPage) StreamProjects() (<-chan Project, func()) {
+	return p.StreamItems()
+}
+
+// Page of expenses, see Page for navigating it
+type ExpensesPage = Page[Expense]
+
+// Page of tags, see Page for navigating it
+type TagsPage = Page[Tag]
+
+// Page of users, see Page for navigating it
+type UsersPage = Page[User]
+
+// Page of invoices, see Page for navigating it
+type InvoicesPage = Page[Invoice]
+
+// Link from an RFC 8288 Link header
+type Link struct {
+	// Target URL of the link, as it appears between the angle brackets
+	URL string
+	// Relation types from the rel parameter, in lower case
+	Rel []string
+	// All parameters of the link, with their names in lower case. Only the
+	// first occurrence of a parameter is kept.
+	Params map[string]string
+}
+
+// Check if the link has a relation type, e.g. "next"
+func (l Link) HasRel(rel string) bool {
+	return slices.Contains(l.Rel, strings.ToLower(rel))
+}
+
+// Parse the value of one or more Link headers, joined with commas. The
+// parser is lenient: it accepts extra parameters, quoted or unquoted values,
+// multiple relation types in one rel parameter and whitespace around every
+// delimiter, and skips over links it can not make sense of.
+func ParseLinkHeader(header string) []Link {
+	var links []Link
+	s := header
+	for {
+		s = strings.TrimLeft(s, " \t\r\n,")
+		if s == "" {
+			return links
+		}
+		if s[0] != '<' {
+			s = skipLink(s)
+			continue
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return links
+		}
+		link := Link{URL: strings.TrimSpace(s[1:end]), Params: make(map[string]string)}
+		s = link.parseParams(s[end+1:])
+		link.Rel = strings.Fields(strings.ToLower(link.Params["rel"]))
+		links = append(links, link)
+	}
+}
+
+// parse the parameters of a link, returning the rest of the header
+func (l *Link) parseParams(s string) string {
+	for {
+		s = trimSpace(s)
+		if s == "" || s[0] == ',' {
+			return s
+		}
+		if s[0] != ';' {
+			return skipLink(s)
+		}
+		var name, value string
+		name, s = linkToken(trimSpace(s[1:]))
+		s = trimSpace(s)
+		if s != "" && s[0] == '=' {
+			s = trimSpace(s[1:])
+			if s != "" && s[0] == '"' {
+				value, s = quotedString(s)
+			} else {
+				value, s = linkToken(s)
+			}
+		}
+		name = strings.ToLower(name)
+		if _, seen := l.Params[name]; name != "" && !seen {
+			l.Params[name] = value
+		}
+	}
+}
+
+// read a token or unquoted value up to the next delimiter
+func linkToken(s string) (string, string) {
+	end := strings.IndexAny(s, "=;, \t\r\n")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// read a quoted string, handling backslash escapes
+func quotedString(s string) (string, string) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:]
+		default:
+			b.WriteByte(s[i])
+		}
 	}
+	return b.String(), ""
+}
+
+// skip to the next link, ignoring commas in quoted strings and URLs
+func skipLink(s string) string {
+	quoted, bracketed := false, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"' && !bracketed:
+			quoted = !quoted
+		case c == '<' && !quoted:
+			bracketed = true
+		case c == '>' && !quoted:
+			bracketed = false
+		case c == ',' && !quoted && !bracketed:
+			return s[i:]
+		}
+	}
+	return ""
+}
 
-	return result, f.doHttpRequest(req, result.onResponse)
+// trim optional whitespace
+func trimSpace(s string) string {
+	return strings.TrimLeft(s, " \t\r\n")
 }
 
-// parse pagination links out of link header text
+// parse pagination links out of link header text, keeping the first
+// link for every relation type
 func pagelinks(header string) map[string]string {
 	result := make(map[string]string)
-	re := regexp.MustCompile("<(.*?)>; rel=\"(.*?)\"")
-	for _, line := range re.FindAllStringSubmatch(header, -1) {
-		rel := line[2]
-		url := line[1]
-		result[rel] = url
+	for _, link := range ParseLinkHeader(header) {
+		for _, rel := range link.Rel {
+			if _, seen := result[rel]; !seen {
+				result[rel] = link.URL
+			}
+		}
 	}
 	return result
 }
@@ -5,7 +5,15 @@
 package freckle
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,3 +30,545 @@ func TestPagelinks(t *testing.T) {
 	assert.Equal(t, "https://apitest.letsfreckle.com/api/v2/users/?page=1&per_page=100", links["first"])
 	assert.Equal(t, "https://apitest.letsfreckle.com/api/v2/users/?page=50&per_page=100", links["last"])
 }
+
+func TestParseLinkHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		links  []Link
+	}{
+		{
+			name:   "empty",
+			header: "",
+			links:  nil,
+		},
+		{
+			name:   "single link",
+			header: `<https://api.letsfreckle.com/v2/entries?page=2>; rel="next"`,
+			links: []Link{
+				{URL: "https://api.letsfreckle.com/v2/entries?page=2", Rel: []string{"next"}, Params: map[string]string{"rel": "next"}},
+			},
+		},
+		{
+			name:   "unquoted rel",
+			header: `<https://a.test/?page=2>; rel=next, <https://a.test/?page=9>;rel=last`,
+			links: []Link{
+				{URL: "https://a.test/?page=2", Rel: []string{"next"}, Params: map[string]string{"rel": "next"}},
+				{URL: "https://a.test/?page=9", Rel: []string{"last"}, Params: map[string]string{"rel": "last"}},
+			},
+		},
+		{
+			name:   "multiple rels in one link",
+			header: `<https://a.test/?page=9>; rel="next last"`,
+			links: []Link{
+				{URL: "https://a.test/?page=9", Rel: []string{"next", "last"}, Params: map[string]string{"rel": "next last"}},
+			},
+		},
+		{
+			name:   "extra parameters",
+			header: `<https://a.test/?page=2>; title="Page 2; of 9, more"; rel="next"; type=application/json; crossorigin`,
+			links: []Link{
+				{URL: "https://a.test/?page=2", Rel: []string{"next"}, Params: map[string]string{
+					"title": "Page 2; of 9, more", "rel": "next", "type": "application/json", "crossorigin": "",
+				}},
+			},
+		},
+		{
+			name:   "whitespace variations",
+			header: "  < https://a.test/?page=2 >\t;\trel = \"next\" ,\r\n\t<https://a.test/?page=1>   ;   REL=\"First\"  ",
+			links: []Link{
+				{URL: "https://a.test/?page=2", Rel: []string{"next"}, Params: map[string]string{"rel": "next"}},
+				{URL: "https://a.test/?page=1", Rel: []string{"first"}, Params: map[string]string{"rel": "First"}},
+			},
+		},
+		{
+			name:   "commas in URLs",
+			header: `<https://a.test/?ids=1,2,3&page=2>; rel="next", <https://a.test/?ids=1,2,3&page=1>; rel="prev"`,
+			links: []Link{
+				{URL: "https://a.test/?ids=1,2,3&page=2", Rel: []string{"next"}, Params: map[string]string{"rel": "next"}},
+				{URL: "https://a.test/?ids=1,2,3&page=1", Rel: []string{"prev"}, Params: map[string]string{"rel": "prev"}},
+			},
+		},
+		{
+			name:   "escaped quotes",
+			header: `<https://a.test/>; title="say \"hi\", please"; rel=next`,
+			links: []Link{
+				{URL: "https://a.test/", Rel: []string{"next"}, Params: map[string]string{"title": `say "hi", please`, "rel": "next"}},
+			},
+		},
+		{
+			name:   "only first rel parameter counts",
+			header: `<https://a.test/>; rel=next; rel=prev`,
+			links: []Link{
+				{URL: "https://a.test/", Rel: []string{"next"}, Params: map[string]string{"rel": "next"}},
+			},
+		},
+		{
+			name:   "malformed links are skipped",
+			header: `garbage; rel="x, y", <https://a.test/?page=2>; rel="next" junk, <https://a.test/?page=3`,
+			links: []Link{
+				{URL: "https://a.test/?page=2", Rel: []string{"next"}, Params: map[string]string{"rel": "next"}},
+			},
+		},
+		{
+			name:   "link without parameters",
+			header: `<https://a.test/>`,
+			links: []Link{
+				{URL: "https://a.test/", Rel: []string{}, Params: map[string]string{}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.links, ParseLinkHeader(test.header))
+		})
+	}
+}
+
+func TestLinkHasRel(t *testing.T) {
+	link := ParseLinkHeader(`<https://a.test/>; rel="next last"`)[0]
+	assert.True(t, link.HasRel("next"))
+	assert.True(t, link.HasRel("LAST"))
+	assert.False(t, link.HasRel("prev"))
+}
+
+func TestPagelinksKeepsFirstLink(t *testing.T) {
+	links := pagelinks(`<https://a.test/?page=2>; rel="next last", <https://a.test/?page=9>; rel="last"`)
+	assert.Equal(t, map[string]string{"next": "https://a.test/?page=2", "last": "https://a.test/?page=2"}, links)
+}
+
+func TestPagelinksFromMultipleHeaders(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", "<https://a.test/?page=2>; rel=next")
+		w.Header().Add("Link", "<https://a.test/?page=5>; rel=last")
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	assert.True(t, page.HasNext(), "Should have a next page")
+	assert.True(t, page.has(LastPage), "Should have a last page")
+}
+
+func TestGenericPage(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		}
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	var ops []string
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		ops = append(ops, op.String())
+		return next(req)
+	})
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+
+	// a TagsPage is a Page, so it can be used in generic code
+	var generic Page[Tag] = page
+	next, err := generic.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(next.Items), "Should have one tag")
+	assert.False(t, next.HasNext(), "Should not have a next page")
+
+	assert.Equal(t, []string{"TagsAPI.ListTags", "TagsPage.Next"}, ops)
+
+	items := 0
+	for _, err := range page.All() {
+		assert.Nil(t, err, "Error should be nil")
+		items++
+	}
+	assert.Equal(t, 2, items, "Should have read the tags on both pages")
+}
+
+// server with a number of pages of tags, with the tag ids matching the page numbers
+func pagedTags(t *testing.T, pages int, last bool, delay time.Duration, inflight *atomic.Int32, peak *atomic.Int32) *httptest.Server {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		n := inflight.Add(1)
+		defer inflight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		if page > pages {
+			w.WriteHeader(404)
+			response(server_error)(w, r)
+			return
+		}
+		link := fmt.Sprintf("<%s/tags?page=1>; rel=\"first\"", ts.URL)
+		if page < pages {
+			link += fmt.Sprintf(", <%s/tags?page=%d>; rel=\"next\"", ts.URL, page+1)
+		}
+		if last {
+			link += fmt.Sprintf(", <%s/tags?page=%d>; rel=\"last\"", ts.URL, pages)
+		}
+		w.Header().Set("Link", link)
+		response(fmt.Sprintf(`[{"id": %d}, {"id": %d}]`, page*10, page*10+1))(w, r)
+	}))
+	return ts
+}
+
+func collectTags(t *testing.T, seq func(func(Tag, error) bool)) []int {
+	var ids []int
+	for tag, err := range seq {
+		assert.Nil(t, err, "Error should be nil")
+		ids = append(ids, tag.Id)
+	}
+	return ids
+}
+
+func expectedTagIds(pages int) []int {
+	var ids []int
+	for page := 1; page <= pages; page++ {
+		ids = append(ids, page*10, page*10+1)
+	}
+	return ids
+}
+
+func TestPrefetchPages(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 5, false, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, expectedTagIds(5), collectTags(t, page.Prefetch(2)))
+}
+
+func TestPrefetchPagesWithError(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 3, false, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	// link to a page the server does not have
+	page.links[NextPage] = ts.URL + "/tags?page=5"
+
+	var ids []int
+	var errs []error
+	for tag, err := range page.Prefetch(2) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, tag.Id)
+	}
+	assert.Equal(t, expectedTagIds(1), ids, "Should have read the page before the error")
+	assert.Equal(t, 1, len(errs), "Should have stopped after the error")
+	assert.True(t, IsNotFound(errs[0]), "Should get the error for the missing page")
+}
+
+func TestPrefetchPagesCancelled(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 5, false, 0, &inflight, &peak)
+	defer ts.Close()
+
+	// the goroutine and the consumer race when the context is cancelled,
+	// so repeat to make sure the cancellation is always reported
+	for range 20 {
+		ctx, cancel := context.WithCancel(context.Background())
+		f := letsTestFreckle(ts).WithContext(ctx)
+
+		page, err := f.TagsAPI().ListTags()
+		assert.Nil(t, err, "Error should be nil")
+
+		var ids []int
+		var last error
+		for tag, err := range page.Prefetch(2) {
+			if err != nil {
+				last = err
+				continue
+			}
+			ids = append(ids, tag.Id)
+			if tag.Id == 20 {
+				cancel()
+			}
+		}
+		assert.ErrorIs(t, last, context.Canceled, "Should report the cancellation")
+		assert.Less(t, len(ids), len(expectedTagIds(5)), "Should not have read all pages")
+		cancel()
+	}
+}
+
+func TestPrefetchPagesStopEarly(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 10, false, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	var requests atomic.Int32
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		requests.Add(1)
+		return next(req)
+	})
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+
+	var ids []int
+	for tag, err := range page.Prefetch(2) {
+		assert.Nil(t, err, "Error should be nil")
+		ids = append(ids, tag.Id)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, expectedTagIds(2)[:3], ids)
+
+	// fetching stops after breaking out of the loop instead of reading ahead
+	time.Sleep(50 * time.Millisecond)
+	assert.LessOrEqual(t, requests.Load(), int32(4), "Should stop fetching pages")
+}
+
+func TestParallelPagesInOrder(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 10, true, 20*time.Millisecond, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	var mu sync.Mutex
+	var ops []string
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		mu.Lock()
+		ops = append(ops, op.String())
+		mu.Unlock()
+		return next(req)
+	})
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+
+	assert.Equal(t, expectedTagIds(10), collectTags(t, page.Parallel(3)))
+	assert.True(t, peak.Load() > 1, "Should have fetched pages in parallel")
+	assert.True(t, peak.Load() <= 3, "Should not have used more than 3 workers")
+	assert.Equal(t, 10, len(ops), "Should have fetched each page once")
+	assert.Equal(t, "TagsPage.Next", ops[9])
+}
+
+func TestParallelWithoutLastPage(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 4, false, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, expectedTagIds(4), collectTags(t, page.Parallel(3)), "Should fall back to prefetching")
+}
+
+func TestParallelPagesStopEarly(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 10, true, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+
+	var ids []int
+	for tag, err := range page.Parallel(2) {
+		assert.Nil(t, err, "Error should be nil")
+		ids = append(ids, tag.Id)
+		if len(ids) == 5 {
+			break
+		}
+	}
+	assert.Equal(t, expectedTagIds(3)[:5], ids)
+}
+
+func TestParallelPagesWithError(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 3, true, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	// pretend there are more pages than the server has
+	page.links[LastPage] = ts.URL + "/tags?page=5"
+
+	var ids []int
+	var errs []error
+	for tag, err := range page.Parallel(2) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ids = append(ids, tag.Id)
+	}
+	assert.Equal(t, expectedTagIds(3), ids, "Should have read the pages before the error")
+	assert.Equal(t, 1, len(errs), "Should have stopped after the error")
+	assert.True(t, IsNotFound(errs[0]), "Should get the error for the missing page")
+}
+
+func TestPageMetadata(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("per_page"), "Should ask for 2 tags per page")
+		link := func(page int, rel string) string {
+			return fmt.Sprintf("<%s/tags?page=%d&per_page=2>; rel=%q", ts.URL, page, rel)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", link(2, "next")+", "+link(3, "last"))
+		case "2":
+			w.Header().Set("Link", link(1, "prev")+", "+link(3, "next")+", "+link(3, "last"))
+		case "3":
+			w.Header().Set("Link", link(2, "prev")+", "+link(1, "first"))
+		}
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags(PerPage(2))
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, page.Number())
+	assert.Equal(t, 2, page.PerPage())
+	total, ok := page.TotalPages()
+	assert.True(t, ok, "Total pages should be known")
+	assert.Equal(t, 3, total)
+
+	page, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 2, page.Number())
+	assert.Equal(t, 2, page.PerPage())
+
+	page, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 3, page.Number())
+	total, ok = page.TotalPages()
+	assert.True(t, ok, "Total pages should be known on the last page")
+	assert.Equal(t, 3, total)
+}
+
+func TestPageMetadataWithoutLastPage(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s/tags?page=3&per_page=50>; rel=\"next\"", ts.URL))
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 2, page.Number(), "Page number should be derived from the next link")
+	assert.Equal(t, 50, page.PerPage(), "Page size should be derived from the next link")
+	_, ok := page.TotalPages()
+	assert.False(t, ok, "Total pages should not be known")
+}
+
+func TestCollectFailsEarly(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 10, true, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	requests := 0
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		requests++
+		return next(req)
+	})
+
+	page, err := f.TagsAPI().ListTags(PerPage(2))
+	assert.Nil(t, err, "Error should be nil")
+
+	_, err = page.Collect(15)
+	assert.ErrorIs(t, err, ErrTooManyItems)
+	assert.Equal(t, 1, requests, "Should not have fetched the other pages")
+
+	tags, err := page.Collect(20)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, expectedTagIds(10), collectIds(tags))
+}
+
+func collectIds(tags []Tag) []int {
+	var ids []int
+	for _, tag := range tags {
+		ids = append(ids, tag.Id)
+	}
+	return ids
+}
+
+func TestParallelPagesHoldsSlotUntilConsumed(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 5, true, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	var mu sync.Mutex
+	requested := 0
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+		mu.Lock()
+		requested = max(requested, page)
+		mu.Unlock()
+		return next(req)
+	})
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+
+	for tag, err := range page.Parallel(1) {
+		assert.Nil(t, err, "Error should be nil")
+		if current := tag.Id / 10; current > 1 {
+			// give a page fetched too early the time to show up
+			time.Sleep(10 * time.Millisecond)
+			mu.Lock()
+			assert.Equal(t, current, requested, "Should not fetch the next page before this one has been consumed")
+			mu.Unlock()
+		}
+	}
+}
+
+func TestCollectWhenServerIgnoresPerPage(t *testing.T) {
+	var inflight, peak atomic.Int32
+	ts := pagedTags(t, 10, true, 0, &inflight, &peak)
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	// the server only returns 2 tags per page, whatever is asked for
+	page, err := f.TagsAPI().ListTags(PerPage(100))
+	assert.Nil(t, err, "Error should be nil")
+
+	tags, err := page.Collect(50)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, expectedTagIds(10), collectIds(tags))
+
+	_, err = page.Collect(19)
+	assert.ErrorIs(t, err, ErrTooManyItems)
+}
@@ -0,0 +1,92 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type ProjectGroupsAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (g ProjectGroupsAPI) op(method string) Operation {
+	return Operation{Resource: "ProjectGroupsAPI", Method: method}
+}
+
+func (g ProjectGroupsAPI) ListProjectGroups(fns ...ParameterSetter) ([]ProjectGroup, error) {
+	var result []ProjectGroup
+	return result, g.freckle.do(g.op("ListProjectGroups"), "GET", "/project_groups", parameters(fns), nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (g ProjectGroupsAPI) GetProjectGroup(id int) (ProjectGroup, error) {
+	var result ProjectGroup
+	return result, g.freckle.do(g.op("GetProjectGroup"), "GET", fmt.Sprintf("/project_groups/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (g ProjectGroupsAPI) CreateProjectGroup(name string, fns ...InputSetter) (ProjectGroup, error) {
+	is := inputs(fns)
+	is["name"] = name
+
+	var result ProjectGroup
+	return result, g.freckle.do(g.op("CreateProjectGroup"), "POST", "/project_groups", nil, is,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (g ProjectGroupsAPI) EditProjectGroup(id int, fns ...InputSetter) (ProjectGroup, error) {
+	var result ProjectGroup
+	return result, g.freckle.do(g.op("EditProjectGroup"), "PUT", fmt.Sprintf("/project_groups/%d", id), nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (g ProjectGroupsAPI) DeleteProjectGroup(id int) error {
+	return g.freckle.do(g.op("DeleteProjectGroup"), "DELETE", fmt.Sprintf("/project_groups/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (g ProjectGroupsAPI) GetProjects(id int) (ProjectsPage, error) {
+	result := emptyProjectsPage(g.freckle)
+	return result, g.freckle.do(g.op("GetProjects"), "GET", fmt.Sprintf("/project_groups/%d/projects", id), nil, nil, result.onResponse)
+}
+
+func (g ProjectGroupsAPI) GetEntries(id int) (EntriesPage, error) {
+	result := emptyEntriesPage(g.freckle)
+	return result, g.freckle.do(g.op("GetEntries"), "GET", fmt.Sprintf("/project_groups/%d/entries", id), nil, nil, result.onResponse)
+}
+
+func (g ProjectGroupsAPI) AssociateProjects(id int, projects ...int) error {
+	is := make(Inputs)
+	is["project_ids"] = projects
+
+	return g.freckle.do(g.op("AssociateProjects"), "PUT", fmt.Sprintf("/project_groups/%d/associate_projects", id), nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (g ProjectGroupsAPI) DisassociateProjects(id int, projects ...int) error {
+	is := make(Inputs)
+	is["project_ids"] = projects
+
+	return g.freckle.do(g.op("DisassociateProjects"), "PUT", fmt.Sprintf("/project_groups/%d/disassociate_projects", id), nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
@@ -0,0 +1,156 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListProjectGroups(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/project_groups", response(array_of_project_groups)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	groups, err := f.ProjectGroupsAPI().ListProjectGroups()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(groups), "Should have one project group")
+	assert.Equal(t, "Sprockets, Inc.", groups[0].Name, "Project group name mismatch")
+	assert.Equal(t, 1, len(groups[0].Projects), "Should have one project in the group")
+}
+
+func TestGetProjectGroup(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/project_groups/3768", response(single_project_group)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ProjectGroupsAPI().GetProjectGroup(3768)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestCreateProjectGroup(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/project_groups", response(single_project_group)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ProjectGroupsAPI().CreateProjectGroup("Sprockets, Inc.")
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestEditProjectGroup(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/project_groups/3768", response(single_project_group)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.ProjectGroupsAPI().EditProjectGroup(3768, func(i Inputs) {
+		i["name"] = "Gears, Inc."
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeleteProjectGroup(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/project_groups/3768", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.ProjectGroupsAPI().DeleteProjectGroup(3768)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestGetProjectGroupProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/project_groups/3768/projects", response(array_of_projects)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ProjectGroupsAPI().GetProjects(3768)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Projects), "Should have one project")
+}
+
+func TestGetProjectGroupEntries(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/project_groups/3768/entries", response(array_of_entries)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ProjectGroupsAPI().GetEntries(3768)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
+}
+
+func TestAssociateProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/project_groups/3768/associate_projects", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]int
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body), "Body should be valid JSON")
+		assert.Equal(t, []int{1234, 4567}, body["project_ids"])
+		noContent()(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.ProjectGroupsAPI().AssociateProjects(3768, 1234, 4567)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDisassociateProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/project_groups/3768/disassociate_projects", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.ProjectGroupsAPI().DisassociateProjects(3768, 1234, 4567)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+const array_of_project_groups = `[
+  {
+    "id": 3768,
+    "name": "Sprockets, Inc.",
+    "url": "https://api.letsfreckle.com/v2/project_groups/3768",
+    "projects": [
+      {
+        "id": 37396,
+        "name": "Gear GmbH",
+        "billing_increment": 10,
+        "enabled": true,
+        "billable": true,
+        "color": "#ff9898",
+        "url": "https://api.letsfreckle.com/v2/projects/37396"
+      }
+    ],
+    "created_at": "2012-01-09T08:33:29Z",
+    "updated_at": "2012-01-09T08:33:29Z"
+  }
+]`
+
+const single_project_group = `{
+  "id": 3768,
+  "name": "Sprockets, Inc.",
+  "url": "https://api.letsfreckle.com/v2/project_groups/3768",
+  "projects": [
+    {
+      "id": 37396,
+      "name": "Gear GmbH",
+      "billing_increment": 10,
+      "enabled": true,
+      "billable": true,
+      "color": "#ff9898",
+      "url": "https://api.letsfreckle.com/v2/projects/37396"
+    }
+  ],
+  "created_at": "2012-01-09T08:33:29Z",
+  "updated_at": "2012-01-09T08:33:29Z"
+}`
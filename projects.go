@@ -5,6 +5,7 @@
 package freckle
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -14,129 +15,253 @@ type ProjectsAPI struct {
 	freckle *Freckle
 }
 
+// describe an operation of this API for the interceptors
+func (p ProjectsAPI) op(method string) Operation {
+	return Operation{Resource: "ProjectsAPI", Method: method}
+}
+
 func (p ProjectsAPI) ListProjects(fns ...ParameterSetter) (ProjectsPage, error) {
+	return p.list(p.freckle.context(), p.op("ListProjects"), fns)
+}
+
+// List the projects like ListProjects, using the context provided for this
+// request only. Unlike with Freckle.WithContext, the page returned does not
+// keep the context, use its NextContext method to fetch the next page.
+func (p ProjectsAPI) ListProjectsContext(ctx context.Context, fns ...ParameterSetter) (ProjectsPage, error) {
+	return p.list(ctx, p.op("ListProjects"), fns)
+}
+
+// List the projects matching a typed query. Additional ParameterSetter
+// functions can be used for parameters the query doesn't cover.
+func (p ProjectsAPI) QueryProjects(q *ProjectsQuery, fns ...ParameterSetter) (ProjectsPage, error) {
+	return p.query(p.freckle.context(), p.op("QueryProjects"), q, fns)
+}
+
+// Like QueryProjects, using the context provided for this request only
+func (p ProjectsAPI) QueryProjectsContext(ctx context.Context, q *ProjectsQuery, fns ...ParameterSetter) (ProjectsPage, error) {
+	return p.query(ctx, p.op("QueryProjects"), q, fns)
+}
+
+// List all projects matching a typed query, following the pagination. A nil
+// query lists all projects. Fails with ErrTooManyItems if there are more
+// than maxItems projects, see Page.Collect.
+func (p ProjectsAPI) ListAllProjects(q *ProjectsQuery, maxItems int, fns ...ParameterSetter) ([]Project, error) {
+	return p.ListAllProjectsContext(p.freckle.context(), q, maxItems, fns...)
+}
+
+// Like ListAllProjects, using the context provided for this request and for
+// fetching the subsequent pages
+func (p ProjectsAPI) ListAllProjectsContext(ctx context.Context, q *ProjectsQuery, maxItems int, fns ...ParameterSetter) ([]Project, error) {
+	if q == nil {
+		q = NewProjectsQuery()
+	}
+	page, err := p.query(ctx, p.op("ListAllProjects"), q, fns)
+	if err != nil {
+		return nil, err
+	}
+	return page.collect(ctx, maxItems)
+}
+
+// list the projects for an operation
+func (p ProjectsAPI) list(ctx context.Context, op Operation, fns []ParameterSetter) (ProjectsPage, error) {
 	result := emptyProjectsPage(p.freckle)
-	return result, p.freckle.do("GET", "/projects", parameters(fns), nil, result.onResponse)
+	return result, p.freckle.doContext(ctx, op, "GET", "/projects", parameters(fns), nil, result.onResponse)
+}
+
+// list the projects matching a typed query for an operation
+func (p ProjectsAPI) query(ctx context.Context, op Operation, q *ProjectsQuery, fns []ParameterSetter) (ProjectsPage, error) {
+	if err := q.Validate(); err != nil {
+		return emptyProjectsPage(p.freckle), err
+	}
+	return p.list(ctx, op, append([]ParameterSetter{q.apply}, fns...))
 }
 
 func emptyProjectsPage(f *Freckle) ProjectsPage {
-	return ProjectsPage{freckle: f}
+	return ProjectsPage{Page: emptyPage[Project](f, "ProjectsPage")}
 }
 
 func (p *ProjectsPage) onResponse(data []byte, resp *http.Response) error {
-	links := pagelinks(resp.Header.Get("Link"))
-	var projects []Project
-
-	err := json.Unmarshal(data, &projects)
-	p.links = links
-	p.Projects = projects
+	err := p.Page.onResponse(data, resp)
+	p.Projects = p.Items
 	return err
 }
 
 func (p ProjectsAPI) GetProject(id int) (Project, error) {
+	return p.GetProjectContext(p.freckle.context(), id)
+}
+
+// Like GetProject, using the context provided for this request only
+func (p ProjectsAPI) GetProjectContext(ctx context.Context, id int) (Project, error) {
 	var result Project
-	return result, p.freckle.do("GET", fmt.Sprintf("/projects/%d", id), nil, nil,
+	return result, p.freckle.doContext(ctx, p.op("GetProject"), "GET", fmt.Sprintf("/projects/%d", id), nil, nil,
 		func(data []byte, resp *http.Response) error {
 			return json.Unmarshal(data, &result)
 		})
 }
 
 func (p ProjectsAPI) CreateProject(name string, fns ...InputSetter) (Project, error) {
+	return p.CreateProjectContext(p.freckle.context(), name, fns...)
+}
+
+// Like CreateProject, using the context provided for this request only
+func (p ProjectsAPI) CreateProjectContext(ctx context.Context, name string, fns ...InputSetter) (Project, error) {
 	is := inputs(fns)
 	is["name"] = name
 
 	var result Project
-	return result, p.freckle.do("POST", "/projects", nil, is,
+	return result, p.freckle.doContext(ctx, p.op("CreateProject"), "POST", "/projects", nil, is,
 		func(data []byte, resp *http.Response) error {
 			return json.Unmarshal(data, &result)
 		})
 }
 
 func (p ProjectsAPI) GetEntries(id int) (EntriesPage, error) {
+	return p.GetEntriesContext(p.freckle.context(), id)
+}
+
+// Like GetEntries, using the context provided for this request only
+func (p ProjectsAPI) GetEntriesContext(ctx context.Context, id int) (EntriesPage, error) {
 	result := emptyEntriesPage(p.freckle)
-	return result, p.freckle.do("GET", fmt.Sprintf("/projects/%d/entries", id), nil, nil, result.onResponse)
+	return result, p.freckle.doContext(ctx, p.op("GetEntries"), "GET", fmt.Sprintf("/projects/%d/entries", id), nil, nil, result.onResponse)
 }
 
-func (p ProjectsAPI) GetInvoices(id int) ([]Invoice, error) {
-	var result []Invoice
-	return result, p.freckle.do("GET", fmt.Sprintf("/projects/%d/invoices", id), nil, nil,
-		func(data []byte, resp *http.Response) error {
-			return json.Unmarshal(data, &result)
-		})
+func (p ProjectsAPI) GetExpenses(id int) (ExpensesPage, error) {
+	return p.GetExpensesContext(p.freckle.context(), id)
+}
+
+// Like GetExpenses, using the context provided for this request only
+func (p ProjectsAPI) GetExpensesContext(ctx context.Context, id int) (ExpensesPage, error) {
+	result := emptyExpensesPage(p.freckle)
+	return result, p.freckle.doContext(ctx, p.op("GetExpenses"), "GET", fmt.Sprintf("/projects/%d/expenses", id), nil, nil, result.onResponse)
+}
+
+func (p ProjectsAPI) GetInvoices(id int) (InvoicesPage, error) {
+	return p.GetInvoicesContext(p.freckle.context(), id)
+}
+
+// Like GetInvoices, using the context provided for this request only
+func (p ProjectsAPI) GetInvoicesContext(ctx context.Context, id int) (InvoicesPage, error) {
+	result := emptyInvoicesPage(p.freckle)
+	return result, p.freckle.doContext(ctx, p.op("GetInvoices"), "GET", fmt.Sprintf("/projects/%d/invoices", id), nil, nil, result.onResponse)
 }
 
 func (p ProjectsAPI) GetParticipants(id int) ([]Participant, error) {
+	return p.GetParticipantsContext(p.freckle.context(), id)
+}
+
+// Like GetParticipants, using the context provided for this request only
+func (p ProjectsAPI) GetParticipantsContext(ctx context.Context, id int) ([]Participant, error) {
 	var result []Participant
-	return result, p.freckle.do("GET", fmt.Sprintf("/projects/%d/participants", id), nil, nil,
+	return result, p.freckle.doContext(ctx, p.op("GetParticipants"), "GET", fmt.Sprintf("/projects/%d/participants", id), nil, nil,
 		func(data []byte, resp *http.Response) error {
 			return json.Unmarshal(data, &result)
 		})
 }
 
 func (p ProjectsAPI) EditProject(id int, fns ...InputSetter) (Project, error) {
+	return p.EditProjectContext(p.freckle.context(), id, fns...)
+}
+
+// Like EditProject, using the context provided for this request only
+func (p ProjectsAPI) EditProjectContext(ctx context.Context, id int, fns ...InputSetter) (Project, error) {
 	var result Project
-	return result, p.freckle.do("PUT", fmt.Sprintf("/projects/%d", id), nil, inputs(fns),
+	return result, p.freckle.doContext(ctx, p.op("EditProject"), "PUT", fmt.Sprintf("/projects/%d", id), nil, inputs(fns),
 		func(data []byte, resp *http.Response) error {
 			return json.Unmarshal(data, &result)
 		})
 }
 
 func (p ProjectsAPI) MergeProject(target, toMerge int) error {
+	return p.MergeProjectContext(p.freckle.context(), target, toMerge)
+}
+
+// Like MergeProject, using the context provided for this request only
+func (p ProjectsAPI) MergeProjectContext(ctx context.Context, target, toMerge int) error {
 	is := make(Inputs)
 	is["project_id"] = toMerge
 
-	return p.freckle.do("PUT", fmt.Sprintf("/projects/%d/merge", target), nil, is,
+	return p.freckle.doContext(ctx, p.op("MergeProject"), "PUT", fmt.Sprintf("/projects/%d/merge", target), nil, is,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (p ProjectsAPI) DeleteProject(id int) error {
-	return p.freckle.do("DELETE", fmt.Sprintf("/projects/%d", id), nil, nil,
+	return p.DeleteProjectContext(p.freckle.context(), id)
+}
+
+// Like DeleteProject, using the context provided for this request only
+func (p ProjectsAPI) DeleteProjectContext(ctx context.Context, id int) error {
+	return p.freckle.doContext(ctx, p.op("DeleteProject"), "DELETE", fmt.Sprintf("/projects/%d", id), nil, nil,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (p ProjectsAPI) ArchiveProject(id int) error {
-	return p.freckle.do("PUT", fmt.Sprintf("/projects/%d/archive", id), nil, nil,
+	return p.ArchiveProjectContext(p.freckle.context(), id)
+}
+
+// Like ArchiveProject, using the context provided for this request only
+func (p ProjectsAPI) ArchiveProjectContext(ctx context.Context, id int) error {
+	return p.freckle.doContext(ctx, p.op("ArchiveProject"), "PUT", fmt.Sprintf("/projects/%d/archive", id), nil, nil,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (p ProjectsAPI) UnarchiveProject(id int) error {
-	return p.freckle.do("PUT", fmt.Sprintf("/projects/%d/unarchive", id), nil, nil,
+	return p.UnarchiveProjectContext(p.freckle.context(), id)
+}
+
+// Like UnarchiveProject, using the context provided for this request only
+func (p ProjectsAPI) UnarchiveProjectContext(ctx context.Context, id int) error {
+	return p.freckle.doContext(ctx, p.op("UnarchiveProject"), "PUT", fmt.Sprintf("/projects/%d/unarchive", id), nil, nil,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (p ProjectsAPI) ArchiveMultipleProjects(ids ...int) error {
+	return p.ArchiveMultipleProjectsContext(p.freckle.context(), ids...)
+}
+
+// Like ArchiveMultipleProjects, using the context provided for this request only
+func (p ProjectsAPI) ArchiveMultipleProjectsContext(ctx context.Context, ids ...int) error {
 	is := make(Inputs)
 	is["project_ids"] = ids
 
-	return p.freckle.do("PUT", "/projects/archive", nil, is,
+	return p.freckle.doContext(ctx, p.op("ArchiveMultipleProjects"), "PUT", "/projects/archive", nil, is,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (p ProjectsAPI) UnarchiveMultipleProjects(ids ...int) error {
+	return p.UnarchiveMultipleProjectsContext(p.freckle.context(), ids...)
+}
+
+// Like UnarchiveMultipleProjects, using the context provided for this request only
+func (p ProjectsAPI) UnarchiveMultipleProjectsContext(ctx context.Context, ids ...int) error {
 	is := make(Inputs)
 	is["project_ids"] = ids
 
-	return p.freckle.do("PUT", "/projects/unarchive", nil, is,
+	return p.freckle.doContext(ctx, p.op("UnarchiveMultipleProjects"), "PUT", "/projects/unarchive", nil, is,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
 }
 
 func (p ProjectsAPI) DeleteMultipleProjects(ids ...int) error {
+	return p.DeleteMultipleProjectsContext(p.freckle.context(), ids...)
+}
+
+// Like DeleteMultipleProjects, using the context provided for this request only
+func (p ProjectsAPI) DeleteMultipleProjectsContext(ctx context.Context, ids ...int) error {
 	is := make(Inputs)
 	is["project_ids"] = ids
 
-	return p.freckle.do("PUT", "/projects/delete", nil, is,
+	return p.freckle.doContext(ctx, p.op("DeleteMultipleProjects"), "PUT", "/projects/delete", nil, is,
 		func(data []byte, resp *http.Response) error {
 			return nil
 		})
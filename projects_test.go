@@ -5,6 +5,7 @@
 package freckle
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -64,6 +65,66 @@ func TestListProjectsThroughChannel(t *testing.T) {
 
 }
 
+func TestListProjectsThroughIterator(t *testing.T) {
+	page := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/projects", func(w http.ResponseWriter, r *http.Request) {
+		page += 1
+		if page < 5 {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, page+1))
+		}
+		response(array_of_projects)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	pp, err := f.ProjectsAPI().ListProjects()
+	assert.Nil(t, err, "Error should be nil")
+	projects := 0
+	for _, err := range pp.All() {
+		assert.Nil(t, err, "Error should be nil")
+		projects += 1
+	}
+	assert.Equal(t, 5, projects, "Should have read 5 projects")
+}
+
+func TestNextProjectsPageWithContext(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/projects", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(array_of_projects)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ProjectsAPI().ListProjects()
+	assert.Nil(t, err, "Error should be nil")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = page.WithContext(ctx).Next()
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestListProjectsContext(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects", response(array_of_projects)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	page, err := f.ProjectsAPI().ListProjectsContext(ctx)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Projects), "Should have one project")
+
+	cancel()
+	_, err = f.ProjectsAPI().ListProjectsContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestListProjectsWithParameters(t *testing.T) {
 	ts := httptest.NewServer(authenticated(t, "GET", "/projects", func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "true", r.URL.Query().Get("billable"))
@@ -102,14 +163,30 @@ func TestListProjectsWithInvalidParameters(t *testing.T) {
 	}
 }
 
+func TestListAllProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects", response(array_of_projects)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	projects, err := f.ProjectsAPI().ListAllProjects(nil, 0)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(projects), "Should have one project")
+	assert.Equal(t, 37396, projects[0].Id, "Project id mismatch")
+}
+
 func TestGetProject(t *testing.T) {
 	ts := httptest.NewServer(authenticated(t, "GET", "/projects/37396", response(single_project)))
 	defer ts.Close()
 
 	f := letsTestFreckle(ts)
 
-	_, err := f.ProjectsAPI().GetProject(37396)
+	project, err := f.ProjectsAPI().GetProject(37396)
 	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, Some(true), project.Enabled, "Enabled mismatch")
+	assert.Equal(t, Some(750), project.BudgetMinutes, "Budget mismatch")
+	assert.Equal(t, 3768, project.Group.Id, "Group mismatch")
+	assert.Equal(t, NewDate(2013, 7, 9), project.Invoices[0].InvoiceDate, "Invoice date mismatch")
 }
 
 func TestCreateProject(t *testing.T) {
@@ -133,15 +210,38 @@ func TestGetEntries(t *testing.T) {
 	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
 }
 
+func TestGetExpenses(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects/37396/expenses", response(array_of_expenses)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ProjectsAPI().GetExpenses(37396)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one expense")
+}
+
 func TestGetInvoices(t *testing.T) {
-	ts := httptest.NewServer(authenticated(t, "GET", "/projects/37396/invoices", response(invoices_for_project)))
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/projects/37396/invoices", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		}
+		response(invoices_for_project)(w, r)
+	}))
 	defer ts.Close()
 
 	f := letsTestFreckle(ts)
 
-	invoices, err := f.ProjectsAPI().GetInvoices(37396)
+	page, err := f.ProjectsAPI().GetInvoices(37396)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one invoice")
+	assert.True(t, page.HasNext(), "Should have a next page")
+
+	page, err = page.Next()
 	assert.Nil(t, err, "Error should be nil")
-	assert.Equal(t, 1, len(invoices), "Should have one invoice")
+	assert.Equal(t, 1, len(page.Items), "Should have one invoice")
+	assert.False(t, page.HasNext(), "Should not have a next page")
 }
 
 func TestGetParticipants(t *testing.T) {
@@ -187,6 +287,21 @@ func TestDeleteProject(t *testing.T) {
 	assert.Nil(t, err, "Error should be nil")
 }
 
+func TestDeleteProjectContext(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/projects/1234", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := f.ProjectsAPI().DeleteProjectContext(cancelled, 1234)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = f.ProjectsAPI().DeleteProjectContext(context.Background(), 1234)
+	assert.Nil(t, err, "Error should be nil")
+}
+
 func TestArchiveProject(t *testing.T) {
 	ts := httptest.NewServer(authenticated(t, "PUT", "/projects/1234/archive", noContent()))
 	defer ts.Close()
@@ -0,0 +1,271 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Typed query for listing entries, to be used with EntriesAPI.QueryEntries
+type EntriesQuery struct {
+	from, to               time.Time
+	updatedFrom, updatedTo time.Time
+	users, projects, tags  []int
+	invoices, imports      []int
+	description            string
+	billable, invoiced     *bool
+}
+
+// Start building a new query for listing entries
+func NewEntriesQuery() *EntriesQuery {
+	return &EntriesQuery{}
+}
+
+// Only entries on or after this date
+func (q *EntriesQuery) From(date time.Time) *EntriesQuery {
+	q.from = date
+	return q
+}
+
+// Only entries on or before this date
+func (q *EntriesQuery) To(date time.Time) *EntriesQuery {
+	q.to = date
+	return q
+}
+
+// Only entries updated at or after this time
+func (q *EntriesQuery) UpdatedFrom(t time.Time) *EntriesQuery {
+	q.updatedFrom = t
+	return q
+}
+
+// Only entries updated at or before this time
+func (q *EntriesQuery) UpdatedTo(t time.Time) *EntriesQuery {
+	q.updatedTo = t
+	return q
+}
+
+// Only entries for these users
+func (q *EntriesQuery) Users(ids ...int) *EntriesQuery {
+	q.users = append(q.users, ids...)
+	return q
+}
+
+// Only entries for these projects
+func (q *EntriesQuery) Projects(ids ...int) *EntriesQuery {
+	q.projects = append(q.projects, ids...)
+	return q
+}
+
+// Only entries with these tags
+func (q *EntriesQuery) Tags(ids ...int) *EntriesQuery {
+	q.tags = append(q.tags, ids...)
+	return q
+}
+
+// Only entries on these invoices
+func (q *EntriesQuery) Invoices(ids ...int) *EntriesQuery {
+	q.invoices = append(q.invoices, ids...)
+	return q
+}
+
+// Only entries created by these imports
+func (q *EntriesQuery) Imports(ids ...int) *EntriesQuery {
+	q.imports = append(q.imports, ids...)
+	return q
+}
+
+// Only entries with a description containing this text
+func (q *EntriesQuery) Description(text string) *EntriesQuery {
+	q.description = text
+	return q
+}
+
+// Only billable or unbillable entries
+func (q *EntriesQuery) Billable(b bool) *EntriesQuery {
+	q.billable = &b
+	return q
+}
+
+// Only invoiced or uninvoiced entries
+func (q *EntriesQuery) Invoiced(b bool) *EntriesQuery {
+	q.invoiced = &b
+	return q
+}
+
+// Check the query for invalid values or combinations
+func (q *EntriesQuery) Validate() error {
+	if err := validRange("from", q.from, "to", q.to); err != nil {
+		return err
+	}
+	if err := validRange("updated_from", q.updatedFrom, "updated_to", q.updatedTo); err != nil {
+		return err
+	}
+	for _, ids := range [][]int{q.users, q.projects, q.tags, q.invoices, q.imports} {
+		if err := validIds(ids); err != nil {
+			return err
+		}
+	}
+	if len(q.invoices) > 0 && q.invoiced != nil && !*q.invoiced {
+		return errors.New("freckle: can not query uninvoiced entries on invoices")
+	}
+	return nil
+}
+
+// add the query to the API parameters
+func (q *EntriesQuery) apply(p Parameters) {
+	setDate(p, "from", q.from)
+	setDate(p, "to", q.to)
+	setTime(p, "updated_from", q.updatedFrom)
+	setTime(p, "updated_to", q.updatedTo)
+	setIds(p, "users", q.users)
+	setIds(p, "projects", q.projects)
+	setIds(p, "tags", q.tags)
+	setIds(p, "invoices", q.invoices)
+	setIds(p, "imports", q.imports)
+	setString(p, "description", q.description)
+	setBool(p, "billable", q.billable)
+	setBool(p, "invoiced", q.invoiced)
+}
+
+// Fields to sort projects by in a ProjectsQuery
+const (
+	SortProjectsByName      = "name"
+	SortProjectsByCreatedAt = "created_at"
+	SortProjectsByUpdatedAt = "updated_at"
+)
+
+// Typed query for listing projects, to be used with ProjectsAPI.QueryProjects
+type ProjectsQuery struct {
+	name              string
+	groups            []int
+	billable, enabled *bool
+	sortBy            string
+	descending        bool
+}
+
+// Start building a new query for listing projects
+func NewProjectsQuery() *ProjectsQuery {
+	return &ProjectsQuery{}
+}
+
+// Only projects with a name containing this text
+func (q *ProjectsQuery) Name(text string) *ProjectsQuery {
+	q.name = text
+	return q
+}
+
+// Only projects in these project groups
+func (q *ProjectsQuery) ProjectGroups(ids ...int) *ProjectsQuery {
+	q.groups = append(q.groups, ids...)
+	return q
+}
+
+// Only billable or unbillable projects
+func (q *ProjectsQuery) Billable(b bool) *ProjectsQuery {
+	q.billable = &b
+	return q
+}
+
+// Only enabled (true) or archived (false) projects
+func (q *ProjectsQuery) Enabled(b bool) *ProjectsQuery {
+	q.enabled = &b
+	return q
+}
+
+// Sort the projects by one of the SortProjectsBy... fields
+func (q *ProjectsQuery) SortBy(field string, descending bool) *ProjectsQuery {
+	q.sortBy = field
+	q.descending = descending
+	return q
+}
+
+// Check the query for invalid values or combinations
+func (q *ProjectsQuery) Validate() error {
+	if err := validIds(q.groups); err != nil {
+		return err
+	}
+	switch q.sortBy {
+	case "", SortProjectsByName, SortProjectsByCreatedAt, SortProjectsByUpdatedAt:
+	default:
+		return errors.New("freckle: can not sort projects by " + q.sortBy)
+	}
+	return nil
+}
+
+// add the query to the API parameters
+func (q *ProjectsQuery) apply(p Parameters) {
+	setString(p, "name", q.name)
+	setIds(p, "project_groups", q.groups)
+	setBool(p, "billable", q.billable)
+	setBool(p, "enabled", q.enabled)
+	if q.sortBy != "" {
+		p["sort_by"] = q.sortBy
+		p["sort_order"] = "asc"
+		if q.descending {
+			p["sort_order"] = "desc"
+		}
+	}
+}
+
+// check that the start of a range is not after its end
+func validRange(fromName string, from time.Time, toName string, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return errors.New("freckle: " + fromName + " is after " + toName)
+	}
+	return nil
+}
+
+// check that all ids are positive
+func validIds(ids []int) error {
+	for _, id := range ids {
+		if id <= 0 {
+			return errors.New("freckle: invalid id " + strconv.Itoa(id))
+		}
+	}
+	return nil
+}
+
+// set a YYYY-MM-DD date parameter, unless the date is zero
+func setDate(p Parameters, key string, date time.Time) {
+	if !date.IsZero() {
+		p[key] = date.Format("2006-01-02")
+	}
+}
+
+// set an ISO 8601 timestamp parameter, unless the time is zero
+func setTime(p Parameters, key string, t time.Time) {
+	if !t.IsZero() {
+		p[key] = t.UTC().Format(time.RFC3339)
+	}
+}
+
+// set a comma-separated list of ids, unless there are none
+func setIds(p Parameters, key string, ids []int) {
+	if len(ids) > 0 {
+		values := make([]string, len(ids))
+		for i, id := range ids {
+			values[i] = strconv.Itoa(id)
+		}
+		p[key] = strings.Join(values, ",")
+	}
+}
+
+// set a string parameter, unless it's empty
+func setString(p Parameters, key, value string) {
+	if value != "" {
+		p[key] = value
+	}
+}
+
+// set a boolean parameter, unless it's nil
+func setBool(p Parameters, key string, b *bool) {
+	if b != nil {
+		p[key] = strconv.FormatBool(*b)
+	}
+}
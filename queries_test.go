@@ -0,0 +1,123 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntriesQueryParameters(t *testing.T) {
+	q := NewEntriesQuery().
+		From(time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC)).
+		To(time.Date(2014, 11, 30, 0, 0, 0, 0, time.UTC)).
+		UpdatedFrom(time.Date(2014, 12, 1, 9, 30, 0, 0, time.UTC)).
+		Projects(37396, 37397).
+		Tags(249397).
+		Users(5538).
+		Invoices(12345678).
+		Imports(8910).
+		Billable(false)
+
+	ps := parameters([]ParameterSetter{q.apply})
+	assert.Equal(t, Parameters{
+		"from":         "2014-11-01",
+		"to":           "2014-11-30",
+		"updated_from": "2014-12-01T09:30:00Z",
+		"projects":     "37396,37397",
+		"tags":         "249397",
+		"users":        "5538",
+		"invoices":     "12345678",
+		"imports":      "8910",
+		"billable":     "false",
+	}, ps)
+}
+
+func TestEntriesQueryValidation(t *testing.T) {
+	nov1 := time.Date(2014, 11, 1, 0, 0, 0, 0, time.UTC)
+	nov30 := time.Date(2014, 11, 30, 0, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, NewEntriesQuery().Validate(), "Empty query should be valid")
+	assert.Nil(t, NewEntriesQuery().From(nov1).To(nov30).Validate(), "Query should be valid")
+	assert.NotNil(t, NewEntriesQuery().From(nov30).To(nov1).Validate(), "from after to should be invalid")
+	assert.NotNil(t, NewEntriesQuery().UpdatedFrom(nov30).UpdatedTo(nov1).Validate(), "updated_from after updated_to should be invalid")
+	assert.NotNil(t, NewEntriesQuery().Projects(0).Validate(), "Project id 0 should be invalid")
+	assert.NotNil(t, NewEntriesQuery().Invoices(1234).Invoiced(false).Validate(), "Uninvoiced entries on an invoice should be invalid")
+}
+
+func TestQueryEntries(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "37396,37397", r.URL.Query().Get("projects"))
+		assert.Equal(t, "true", r.URL.Query().Get("invoiced"))
+		assert.Equal(t, "freckle", r.URL.Query().Get("description"))
+		response(array_of_entries)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.EntriesAPI().QueryEntries(NewEntriesQuery().Projects(37396, 37397).Invoiced(true), func(p Parameters) {
+		p["description"] = "freckle"
+	})
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
+}
+
+func TestQueryEntriesWithInvalidQuery(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Invalid query should not have been sent")
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().QueryEntries(NewEntriesQuery().Users(-1))
+	assert.NotNil(t, err, "Error should not be nil")
+}
+
+func TestProjectsQueryParameters(t *testing.T) {
+	q := NewProjectsQuery().
+		Name("Gear").
+		ProjectGroups(3768, 3769).
+		Billable(true).
+		Enabled(false).
+		SortBy(SortProjectsByUpdatedAt, true)
+
+	ps := parameters([]ParameterSetter{q.apply})
+	assert.Equal(t, Parameters{
+		"name":           "Gear",
+		"project_groups": "3768,3769",
+		"billable":       "true",
+		"enabled":        "false",
+		"sort_by":        "updated_at",
+		"sort_order":     "desc",
+	}, ps)
+}
+
+func TestProjectsQueryValidation(t *testing.T) {
+	assert.Nil(t, NewProjectsQuery().Validate(), "Empty query should be valid")
+	assert.Nil(t, NewProjectsQuery().SortBy(SortProjectsByName, false).Validate(), "Query should be valid")
+	assert.NotNil(t, NewProjectsQuery().SortBy("colour", false).Validate(), "Unknown sort field should be invalid")
+	assert.NotNil(t, NewProjectsQuery().ProjectGroups(-1).Validate(), "Negative group id should be invalid")
+}
+
+func TestQueryProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("billable"))
+		assert.Equal(t, "3768", r.URL.Query().Get("project_groups"))
+		response(array_of_projects)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.ProjectsAPI().QueryProjects(NewProjectsQuery().Billable(true).ProjectGroups(3768))
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Projects), "Should have one project")
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Policy for retrying API calls that failed because of a transient
+// network error or HTTP status code
+type RetryPolicy struct {
+	// Maximum number of attempts for a single call, including the first one
+	MaxAttempts int
+	// Time to wait before the first retry, doubled for every next retry
+	InitialBackoff time.Duration
+	// Upper limit for the time to wait between two attempts
+	MaxBackoff time.Duration
+	// Fraction (0 to 1) of the backoff that is randomized
+	Jitter float64
+	// HTTP status codes that are considered transient
+	StatusCodes []int
+	// HTTP methods that are safe to send more than once
+	Methods []string
+}
+
+// Get a retry policy that retries idempotent calls up to 3 times on network
+// errors, rate limiting and bad gateway/unavailable/timeout responses
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Jitter:         0.5,
+		StatusCodes:    []int{429, 502, 503, 504},
+		Methods:        []string{"GET", "PUT", "DELETE"},
+	}
+}
+
+// check if another attempt should be made for the request
+func (r RetryPolicy) shouldRetry(attempt int, req *http.Request, resp *http.Response, err error) bool {
+	if attempt >= r.MaxAttempts || !slices.Contains(r.Methods, req.Method) {
+		return false
+	}
+	if err != nil {
+		return req.Context().Err() == nil
+	}
+	return slices.Contains(r.StatusCodes, resp.StatusCode)
+}
+
+// calculate the time to wait after the attempt provided
+func (r RetryPolicy) backoff(attempt int) time.Duration {
+	d := r.InitialBackoff
+	for i := 1; i < attempt && (r.MaxBackoff <= 0 || d < r.MaxBackoff); i++ {
+		d *= 2
+	}
+	if r.MaxBackoff > 0 && d > r.MaxBackoff {
+		d = r.MaxBackoff
+	}
+	if r.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * r.Jitter * float64(d))
+	}
+	return d
+}
+
+// wait before the next attempt, unless the context is done first
+func (r RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(r.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func letsTestFreckleWithRetries(ts *httptest.Server) Freckle {
+	f := letsTestFreckle(ts)
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = time.Millisecond
+	f.Retry(policy)
+	return f
+}
+
+func TestRetryOnBadGateway(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts += 1
+		if attempts == 1 {
+			w.WriteHeader(502)
+			return
+		}
+		response(single_entry)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckleWithRetries(ts)
+
+	var seen []int
+	f.Intercept(func(op Operation, req *http.Request, next RoundTrip) (*http.Response, error) {
+		seen = append(seen, op.Attempt)
+		return next(req)
+	})
+
+	entry, err := f.EntriesAPI().GetEntry(1)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, entry.Id, "Entry id mismatch")
+	assert.Equal(t, 2, attempts, "Should have retried once")
+	assert.Equal(t, []int{1, 2}, seen, "Interceptors should see every attempt")
+}
+
+func TestRetryReplaysRequestBody(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(authenticated(t, "PUT", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts += 1
+		data, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"description":"Not so hard #support question"}`, string(data))
+		if attempts < 3 {
+			w.WriteHeader(503)
+			return
+		}
+		response(single_entry)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckleWithRetries(ts)
+
+	_, err := f.EntriesAPI().EditEntry(1, func(i Inputs) {
+		i["description"] = "Not so hard #support question"
+	})
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 3, attempts, "Should have retried twice")
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts += 1
+		w.WriteHeader(504)
+		response(server_error)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckleWithRetries(ts)
+
+	_, err := f.EntriesAPI().GetEntry(1)
+	assert.NotNil(t, err, "Error should not be nil")
+	assert.Equal(t, 3, attempts, "Should have tried 3 times")
+}
+
+func TestNoRetryForNonIdempotentMethods(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(authenticated(t, "POST", "/entries", func(w http.ResponseWriter, r *http.Request) {
+		attempts += 1
+		w.WriteHeader(502)
+		response(server_error)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckleWithRetries(ts)
+
+	_, err := f.EntriesAPI().CreateEntry(NewDate(2014, 12, 18), 60)
+	assert.NotNil(t, err, "Error should not be nil")
+	assert.Equal(t, 1, attempts, "Should not have retried a POST")
+}
+
+func TestNoRetryByDefault(t *testing.T) {
+	attempts := 0
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		attempts += 1
+		w.WriteHeader(502)
+		response(server_error)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().GetEntry(1)
+	assert.NotNil(t, err, "Error should not be nil")
+	assert.Equal(t, 1, attempts, "Should not have retried")
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.backoff(1))
+	assert.Equal(t, 2*time.Second, policy.backoff(2))
+	assert.Equal(t, 4*time.Second, policy.backoff(3))
+	assert.Equal(t, 5*time.Second, policy.backoff(4))
+	assert.Equal(t, 5*time.Second, policy.backoff(100))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := policy.backoff(2)
+		assert.True(t, d > time.Second && d <= 2*time.Second, "Backoff with jitter out of range")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type TagsAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (t TagsAPI) op(method string) Operation {
+	return Operation{Resource: "TagsAPI", Method: method}
+}
+
+func (t TagsAPI) ListTags(fns ...ParameterSetter) (TagsPage, error) {
+	result := emptyTagsPage(t.freckle)
+	return result, t.freckle.do(t.op("ListTags"), "GET", "/tags", parameters(fns), nil, result.onResponse)
+}
+
+func emptyTagsPage(f *Freckle) TagsPage {
+	return emptyPage[Tag](f, "TagsPage")
+}
+
+func (t TagsAPI) GetTag(id int) (Tag, error) {
+	var result Tag
+	return result, t.freckle.do(t.op("GetTag"), "GET", fmt.Sprintf("/tags/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TagsAPI) CreateTags(names ...string) ([]Tag, error) {
+	is := make(Inputs)
+	is["names"] = names
+
+	var result []Tag
+	return result, t.freckle.do(t.op("CreateTags"), "POST", "/tags", nil, is,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TagsAPI) EditTag(id int, fns ...InputSetter) (Tag, error) {
+	var result Tag
+	return result, t.freckle.do(t.op("EditTag"), "PUT", fmt.Sprintf("/tags/%d", id), nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TagsAPI) MergeTags(target, toMerge int) error {
+	is := make(Inputs)
+	is["tag_id"] = toMerge
+
+	return t.freckle.do(t.op("MergeTags"), "PUT", fmt.Sprintf("/tags/%d/merge", target), nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (t TagsAPI) DeleteTag(id int) error {
+	return t.freckle.do(t.op("DeleteTag"), "DELETE", fmt.Sprintf("/tags/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (t TagsAPI) DeleteMultipleTags(ids ...int) error {
+	is := make(Inputs)
+	is["tag_ids"] = ids
+
+	return t.freckle.do(t.op("DeleteMultipleTags"), "PUT", "/tags/delete", nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (t TagsAPI) GetEntries(id int) (EntriesPage, error) {
+	result := emptyEntriesPage(t.freckle)
+	return result, t.freckle.do(t.op("GetEntries"), "GET", fmt.Sprintf("/tags/%d/entries", id), nil, nil, result.onResponse)
+}
@@ -0,0 +1,162 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTags(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one tag")
+	assert.Equal(t, "freckle", page.Items[0].Name, "Tag name mismatch")
+	assert.True(t, page.HasNext(), "Should have a next page")
+
+	page, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one tag")
+}
+
+func TestListTagsThroughChannel(t *testing.T) {
+	page := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		page += 1
+		if page < 10 {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, page+1))
+		}
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	tp, err := f.TagsAPI().ListTags()
+	assert.Nil(t, err, "Error should be nil")
+	tags := 0
+	for _ = range tp.AllItems() {
+		tags += 1
+	}
+	assert.Equal(t, 10, tags, "Should have read 10 tags")
+}
+
+func TestGetTag(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/tags/249397", response(single_tag)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.TagsAPI().GetTag(249397)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestCreateTags(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/tags", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]string
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body), "Body should be valid JSON")
+		assert.Equal(t, []string{"freckle"}, body["names"])
+		response(array_of_tags)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	tags, err := f.TagsAPI().CreateTags("freckle")
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(tags), "Should have one tag")
+}
+
+func TestEditTag(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/tags/249397", response(single_tag)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.TagsAPI().EditTag(249397, func(i Inputs) {
+		i["name"] = "freckle"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestMergeTags(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/tags/1234/merge", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.TagsAPI().MergeTags(1234, 4567)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeleteTag(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/tags/1234", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.TagsAPI().DeleteTag(1234)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeleteMultipleTags(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/tags/delete", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.TagsAPI().DeleteMultipleTags(1234, 4567)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestGetTagEntries(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/tags/249397/entries", response(array_of_entries)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.TagsAPI().GetEntries(249397)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Entries), "Should have one entry")
+}
+
+const array_of_tags = `[
+  {
+    "id": 249397,
+    "name": "freckle",
+    "billable": true,
+    "url": "https://api.letsfreckle.com/v2/tags/249397",
+    "entries": 0,
+    "entries_url": "https://api.letsfreckle.com/v2/tags/249397/entries",
+    "created_at": "2012-01-09T08:33:29Z",
+    "updated_at": "2012-01-09T08:33:29Z"
+  }
+]`
+
+const single_tag = `{
+  "id": 249397,
+  "name": "freckle",
+  "billable": true,
+  "url": "https://api.letsfreckle.com/v2/tags/249397",
+  "entries": 0,
+  "entries_url": "https://api.letsfreckle.com/v2/tags/249397/entries",
+  "created_at": "2012-01-09T08:33:29Z",
+  "updated_at": "2012-01-09T08:33:29Z"
+}`
@@ -0,0 +1,146 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate limit information, as last reported by the Freckle API
+type RateLimit struct {
+	// Number of requests allowed in the current window
+	Limit int
+	// Number of requests left in the current window
+	Remaining int
+	// Time at which the current window resets
+	Reset time.Time
+	// Time until which requests are held back after a 429 response
+	RetryAfter time.Time
+	// Time at which this information was received
+	ObservedAt time.Time
+}
+
+// Token bucket shared by all copies of a Freckle client, which also keeps
+// track of the rate limit information returned by the API
+type limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	state  RateLimit
+}
+
+func newLimiter() *limiter {
+	return &limiter{}
+}
+
+// configure the number of requests per second and the burst size,
+// a rate of 0 disables client-side throttling
+func (l *limiter) configure(rate float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if burst < 1 {
+		burst = 1
+	}
+	l.rate = rate
+	l.burst = float64(burst)
+	l.tokens = float64(burst)
+	l.last = time.Now()
+}
+
+// take a token from the bucket and get the time to wait before using it
+func (l *limiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	if l.rate > 0 {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		l.tokens -= 1
+		if l.tokens < 0 {
+			wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		}
+	}
+	if pause := l.state.RetryAfter.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait
+}
+
+// wait until the next request can be sent, unless the context is done first
+func (l *limiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	d := l.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// record the rate limit information from the response headers
+func (l *limiter) observe(resp *http.Response) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		l.state.Limit = limit
+		l.state.ObservedAt = now
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		l.state.Remaining = remaining
+		l.state.ObservedAt = now
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		l.state.Reset = time.Unix(reset, 0)
+		l.state.ObservedAt = now
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		l.state.RetryAfter = now.Add(retryAfter(resp.Header.Get("Retry-After"), now))
+		l.state.ObservedAt = now
+	}
+}
+
+// get a copy of the last rate limit information
+func (l *limiter) rateLimit() RateLimit {
+	if l == nil {
+		return RateLimit{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
+
+// parse a Retry-After header, either in seconds or as an HTTP date
+func retryAfter(header string, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	l := newLimiter()
+	l.configure(2, 2)
+	now := l.last
+
+	assert.Equal(t, time.Duration(0), l.reserve(now), "First request fits in the burst")
+	assert.Equal(t, time.Duration(0), l.reserve(now), "Second request fits in the burst")
+	assert.Equal(t, 500*time.Millisecond, l.reserve(now), "Third request has to wait for a new token")
+
+	// after 1.5 seconds, the bucket has been refilled with 3 tokens
+	now = now.Add(1500 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), l.reserve(now), "Bucket should have been refilled")
+}
+
+func TestNoThrottlingByDefault(t *testing.T) {
+	l := newLimiter()
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), l.reserve(now), "Should not wait without throttling")
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	l := newLimiter()
+	resp := &http.Response{StatusCode: 429, Header: make(http.Header)}
+	resp.Header.Set("Retry-After", "30")
+	l.observe(resp)
+
+	wait := l.reserve(time.Now())
+	assert.True(t, wait > 29*time.Second && wait <= 30*time.Second, "Should wait for the Retry-After period")
+	assert.False(t, l.rateLimit().RetryAfter.IsZero(), "Retry-After should have been recorded")
+
+	now := time.Now()
+	assert.Equal(t, 90*time.Second, retryAfter("90", now))
+	assert.Equal(t, time.Minute, retryAfter(now.Add(time.Minute).UTC().Format(http.TimeFormat), now.Truncate(time.Second)))
+	assert.Equal(t, time.Duration(0), retryAfter("soon", now))
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "1419206400")
+		response(single_entry)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.EntriesAPI().GetEntry(1)
+	assert.Nil(t, err, "Error should be nil")
+
+	limit := f.RateLimit()
+	assert.Equal(t, 100, limit.Limit, "Rate limit mismatch")
+	assert.Equal(t, 42, limit.Remaining, "Remaining requests mismatch")
+	assert.Equal(t, time.Unix(1419206400, 0), limit.Reset, "Reset time mismatch")
+}
+
+func TestThrottledRequests(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/entries/1", response(single_entry)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+	f.Throttle(20, 1)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := f.EntriesAPI().GetEntry(1)
+		assert.Nil(t, err, "Error should be nil")
+	}
+	assert.True(t, time.Since(start) >= 190*time.Millisecond, "5 requests at 20/s should take about 200ms")
+}
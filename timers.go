@@ -0,0 +1,74 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type TimersAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (t TimersAPI) op(method string) Operation {
+	return Operation{Resource: "TimersAPI", Method: method}
+}
+
+func (t TimersAPI) ListTimers(fns ...ParameterSetter) ([]Timer, error) {
+	var result []Timer
+	return result, t.freckle.do(t.op("ListTimers"), "GET", "/timers", parameters(fns), nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TimersAPI) GetTimer(projectId int) (Timer, error) {
+	var result Timer
+	return result, t.freckle.do(t.op("GetTimer"), "GET", fmt.Sprintf("/projects/%d/timer", projectId), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TimersAPI) StartTimer(projectId int) (Timer, error) {
+	var result Timer
+	return result, t.freckle.do(t.op("StartTimer"), "PUT", fmt.Sprintf("/projects/%d/timer/start", projectId), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TimersAPI) PauseTimer(projectId int) (Timer, error) {
+	var result Timer
+	return result, t.freckle.do(t.op("PauseTimer"), "PUT", fmt.Sprintf("/projects/%d/timer/pause", projectId), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TimersAPI) LogTimer(projectId int, fns ...InputSetter) error {
+	return t.freckle.do(t.op("LogTimer"), "PUT", fmt.Sprintf("/projects/%d/timer/log", projectId), nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (t TimersAPI) EditTimer(projectId int, fns ...InputSetter) (Timer, error) {
+	var result Timer
+	return result, t.freckle.do(t.op("EditTimer"), "PUT", fmt.Sprintf("/projects/%d/timer", projectId), nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (t TimersAPI) DiscardTimer(projectId int) error {
+	return t.freckle.do(t.op("DiscardTimer"), "DELETE", fmt.Sprintf("/projects/%d/timer", projectId), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
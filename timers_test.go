@@ -0,0 +1,158 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTimers(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/timers", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "running", r.URL.Query().Get("state"))
+		response(array_of_timers)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	timers, err := f.TimersAPI().ListTimers(func(p Parameters) {
+		p["state"] = "running"
+	})
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(timers), "Should have one timer")
+	assert.Equal(t, "running", timers[0].State, "Timer state mismatch")
+	assert.Equal(t, 37396, timers[0].Project.Id, "Timer project mismatch")
+}
+
+func TestGetTimer(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/projects/37396/timer", response(single_timer)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	timer, err := f.TimersAPI().GetTimer(37396)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 3600, timer.Seconds, "Timer seconds mismatch")
+}
+
+func TestStartTimer(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/projects/37396/timer/start", response(single_timer)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.TimersAPI().StartTimer(37396)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestPauseTimer(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/projects/37396/timer/pause", response(single_timer)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.TimersAPI().PauseTimer(37396)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestLogTimer(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/projects/37396/timer/log", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.TimersAPI().LogTimer(37396, func(i Inputs) {
+		i["description"] = "Daily #standup"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestEditTimer(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/projects/37396/timer", response(single_timer)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.TimersAPI().EditTimer(37396, func(i Inputs) {
+		i["description"] = "Weekly #standup"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDiscardTimer(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/projects/37396/timer", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.TimersAPI().DiscardTimer(37396)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+const array_of_timers = `[
+  {
+    "id": 123456,
+    "state": "running",
+    "seconds": 3600,
+    "formatted_time": "1:00:00",
+    "date": "2014-12-22",
+    "description": "Daily #standup",
+    "user": {
+      "id": 5538,
+      "email": "john.test@test.com",
+      "first_name": "John",
+      "last_name": "Test",
+      "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+      "url": "https://api.letsfreckle.com/v2/users/5538"
+    },
+    "project": {
+      "id": 37396,
+      "name": "Gear GmbH",
+      "billing_increment": 10,
+      "enabled": true,
+      "billable": true,
+      "color": "#ff9898",
+      "url": "https://api.letsfreckle.com/v2/projects/37396"
+    },
+    "url": "https://api.letsfreckle.com/v2/projects/37396/timer",
+    "start_url": "https://api.letsfreckle.com/v2/projects/37396/timer/start",
+    "pause_url": "https://api.letsfreckle.com/v2/projects/37396/timer/pause",
+    "log_url": "https://api.letsfreckle.com/v2/projects/37396/timer/log"
+  }
+]`
+
+const single_timer = `{
+  "id": 123456,
+  "state": "running",
+  "seconds": 3600,
+  "formatted_time": "1:00:00",
+  "date": "2014-12-22",
+  "description": "Daily #standup",
+  "user": {
+    "id": 5538,
+    "email": "john.test@test.com",
+    "first_name": "John",
+    "last_name": "Test",
+    "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+    "url": "https://api.letsfreckle.com/v2/users/5538"
+  },
+  "project": {
+    "id": 37396,
+    "name": "Gear GmbH",
+    "billing_increment": 10,
+    "enabled": true,
+    "billable": true,
+    "color": "#ff9898",
+    "url": "https://api.letsfreckle.com/v2/projects/37396"
+  },
+  "url": "https://api.letsfreckle.com/v2/projects/37396/timer",
+  "start_url": "https://api.letsfreckle.com/v2/projects/37396/timer/start",
+  "pause_url": "https://api.letsfreckle.com/v2/projects/37396/timer/pause",
+  "log_url": "https://api.letsfreckle.com/v2/projects/37396/timer/log"
+}`
@@ -4,50 +4,104 @@
 
 package freckle
 
+import (
+	"time"
+)
+
 // Shared type definitions for API input/output
 
 type Entry struct {
 	Id          int            `json:"id,omitempty"`
-	Date        string         `json:"date,omitempty"`
-	User        Participant    `json:"user,omitempty"`
-	Billable    bool           `json:"billable,omitempty"`
+	Date        Date           `json:"date,omitzero"`
+	User        Participant    `json:"user,omitzero"`
+	Billable    Optional[bool] `json:"billable,omitzero"`
 	Minutes     int            `json:"minutes,omitempty"`
 	Description string         `json:"description,omitempty"`
-	Project     ProjectSummary `json:"project,omitempty"`
+	Project     ProjectSummary `json:"project,omitzero"`
 	Tags        []Tag          `json:"tags,omitempty"`
 	SourceUrl   string         `json:"source_url,omitempty"`
-	InvoicedAt  string         `json:"invoiced_at,omitempty"`
-	Invoice     Invoice        `json:"invoice,omitempty"`
-	Import      Import         `json:"import,omitempty"`
+	InvoicedAt  time.Time      `json:"invoiced_at,omitzero"`
+	Invoice     Invoice        `json:"invoice,omitzero"`
+	Import      Import         `json:"import,omitzero"`
 	Url         string         `json:"url,omitempty"`
-	CreatedAt   string         `json:"created_at,omitempty"`
-	UpdatedAt   string         `json:"updated_at,omitempty"`
+	CreatedAt   time.Time      `json:"created_at,omitzero"`
+	UpdatedAt   time.Time      `json:"updated_at,omitzero"`
 }
 
-type EntriesPage struct {
-	links   map[string]string
-	freckle *Freckle
-	Entries []Entry
+type Expense struct {
+	Id          int            `json:"id,omitempty"`
+	Date        Date           `json:"date,omitzero"`
+	User        Participant    `json:"user,omitzero"`
+	Amount      float64        `json:"amount,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Project     ProjectSummary `json:"project,omitzero"`
+	InvoicedAt  time.Time      `json:"invoiced_at,omitzero"`
+	Invoice     Invoice        `json:"invoice,omitzero"`
+	ReceiptUrl  string         `json:"receipt_url,omitempty"`
+	Url         string         `json:"url,omitempty"`
+	CreatedAt   time.Time      `json:"created_at,omitzero"`
+	UpdatedAt   time.Time      `json:"updated_at,omitzero"`
 }
 
 type Import struct {
-	Id  int    `json:"id,omitempty"`
-	Url string `json:"url,omitempty"`
+	Id         int         `json:"id,omitempty"`
+	Url        string      `json:"url,omitempty"`
+	FileName   string      `json:"file_name,omitempty"`
+	State      string      `json:"state,omitempty"`
+	User       Participant `json:"user,omitzero"`
+	Entries    int         `json:"entries,omitempty"`
+	EntriesUrl string      `json:"entries_url,omitempty"`
+	CreatedAt  time.Time   `json:"created_at,omitzero"`
+	UpdatedAt  time.Time   `json:"updated_at,omitzero"`
 }
 
+// Invoice holds both the summary that is nested in entries and
+// projects and the full details returned by the Invoices API.
 type Invoice struct {
-	Id          int     `json:"id,omitempty"`
-	Reference   string  `json:"reference,omitempty"`
-	InvoiceDate string  `json:"invoice_date,omitempty"`
-	State       string  `json:"state,omitempty"`
-	TotalAmount float64 `json:"total_amount,omitempty"`
-	Url         string  `json:"url,omitempty"`
+	Id                      int              `json:"id,omitempty"`
+	Reference               string           `json:"reference,omitempty"`
+	InvoiceDate             Date             `json:"invoice_date,omitzero"`
+	State                   string           `json:"state,omitempty"`
+	TotalAmount             float64          `json:"total_amount,omitempty"`
+	Url                     string           `json:"url,omitempty"`
+	Name                    string           `json:"name,omitempty"`
+	CompanyName             string           `json:"company_name,omitempty"`
+	CompanyDetails          string           `json:"company_details,omitempty"`
+	RecipientDetails        string           `json:"recipient_details,omitempty"`
+	Description             string           `json:"description,omitempty"`
+	Footer                  string           `json:"footer,omitempty"`
+	ShowHours               Optional[bool]   `json:"show_hours,omitzero"`
+	ShowDetails             Optional[bool]   `json:"show_details,omitzero"`
+	ShowSummaries           Optional[bool]   `json:"show_summaries,omitzero"`
+	Taxes                   []InvoiceTax     `json:"taxes,omitempty"`
+	AmountTaxable           float64          `json:"amount_taxable,omitempty"`
+	AmountTaxfree           float64          `json:"amount_taxfree,omitempty"`
+	AmountTaxTotal          float64          `json:"amount_tax_total,omitempty"`
+	AmountTotalWithCurrency string           `json:"amount_total_with_currency,omitempty"`
+	ShareUrl                string           `json:"share_url,omitempty"`
+	Projects                []ProjectSummary `json:"projects,omitempty"`
+	Entries                 int              `json:"entries,omitempty"`
+	EntriesUrl              string           `json:"entries_url,omitempty"`
+	Expenses                int              `json:"expenses,omitempty"`
+	ExpensesUrl             string           `json:"expenses_url,omitempty"`
+	CreatedAt               time.Time        `json:"created_at,omitzero"`
+	UpdatedAt               time.Time        `json:"updated_at,omitzero"`
+}
+
+type InvoiceTax struct {
+	Id         int     `json:"id,omitempty"`
+	Name       string  `json:"name,omitempty"`
+	Percentage float64 `json:"percentage,omitempty"`
 }
 
 // Error type returned by Freckle API
 type FreckleError struct {
-	Message string               `json:"message,omitempty"`
-	Errors  []FreckleErrorDetail `json:"errors,omitempty"`
+	Message    string               `json:"message,omitempty"`
+	Errors     []FreckleErrorDetail `json:"errors,omitempty"`
+	StatusCode int                  `json:"-"`
+	Method     string               `json:"-"`
+	URL        string               `json:"-"`
+	Body       []byte               `json:"-"`
 }
 
 type FreckleErrorDetail struct {
@@ -66,56 +120,95 @@ type Participant struct {
 }
 
 type Project struct {
-	Id                int           `json:"id,omitempty"`
-	Name              string        `json:"name,omitempty"`
-	BillingIncrement  int           `json:"billing_increment,omitempty"`
-	Enabled           bool          `json:"enabled,omitempty"`
-	Billable          bool          `json:"billable,omitempty"`
-	Color             string        `json:"color,omitempty"`
-	Url               string        `json:"url,omitempty"`
-	Group             ProjectGroup  `json:"group, omitempty"`
-	Minutes           int           `json:"minutes,omitempty"`
-	BillableMinutes   int           `json:"billable_minutes,omitempty"`
-	UnbillableMinutes int           `json:"unbillable_minutes,omitempty"`
-	InvoicedMinutes   int           `json:"invoiced_minutes,omitempty"`
-	RemainingMinutes  int           `json:"remaining_minutes,omitempty"`
-	BudgetMinutes     int           `json:"budget_minutes,omitempty"`
-	Import            Import        `json:"import,omitempty"`
-	Invoices          []Invoice     `json:"invoices,omitempty"`
-	Participants      []Participant `json:"participants,omitempty"`
-	Entries           int           `json:"entries,omitempty"`
-	EntriesUrl        string        `json:"entries_url,omitempty"`
-	Expenses          int           `json:"expenses,omitempty"`
-	ExpensesUrl       string        `json:"expenses_url,omitempty"`
-	CreatedAt         string        `json:"created_at,omitempty"`
-	UpdatedAt         string        `json:"updated_at,omitempty"`
-}
-
-type ProjectsPage struct {
-	links    map[string]string
-	freckle  *Freckle
-	Projects []Project
+	Id                int            `json:"id,omitempty"`
+	Name              string         `json:"name,omitempty"`
+	BillingIncrement  int            `json:"billing_increment,omitempty"`
+	Enabled           Optional[bool] `json:"enabled,omitzero"`
+	Billable          Optional[bool] `json:"billable,omitzero"`
+	Color             string         `json:"color,omitempty"`
+	Url               string         `json:"url,omitempty"`
+	Group             ProjectGroup   `json:"group,omitzero"`
+	Minutes           int            `json:"minutes,omitempty"`
+	BillableMinutes   int            `json:"billable_minutes,omitempty"`
+	UnbillableMinutes int            `json:"unbillable_minutes,omitempty"`
+	InvoicedMinutes   int            `json:"invoiced_minutes,omitempty"`
+	RemainingMinutes  int            `json:"remaining_minutes,omitempty"`
+	BudgetMinutes     Optional[int]  `json:"budget_minutes,omitzero"`
+	Import            Import         `json:"import,omitzero"`
+	Invoices          []Invoice      `json:"invoices,omitempty"`
+	Participants      []Participant  `json:"participants,omitempty"`
+	Entries           int            `json:"entries,omitempty"`
+	EntriesUrl        string         `json:"entries_url,omitempty"`
+	Expenses          int            `json:"expenses,omitempty"`
+	ExpensesUrl       string         `json:"expenses_url,omitempty"`
+	CreatedAt         time.Time      `json:"created_at,omitzero"`
+	UpdatedAt         time.Time      `json:"updated_at,omitzero"`
 }
 
 type ProjectGroup struct {
-	Id   int    `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
-	Url  string `json:"url,omitempty"`
+	Id        int              `json:"id,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Url       string           `json:"url,omitempty"`
+	Projects  []ProjectSummary `json:"projects,omitempty"`
+	CreatedAt time.Time        `json:"created_at,omitzero"`
+	UpdatedAt time.Time        `json:"updated_at,omitzero"`
 }
 
 type ProjectSummary struct {
-	Id               int    `json:"id,omitempty"`
-	Name             string `json:"name,omitempty"`
-	BillingIncrement int    `json:"billing_increment,omitempty"`
-	Enabled          bool   `json:"enabled,omitempty"`
-	Billable         bool   `json:"billable,omitempty"`
-	Color            string `json:"color,omitempty"`
-	Url              string `json:"url,omitempty"`
+	Id               int            `json:"id,omitempty"`
+	Name             string         `json:"name,omitempty"`
+	BillingIncrement int            `json:"billing_increment,omitempty"`
+	Enabled          Optional[bool] `json:"enabled,omitzero"`
+	Billable         Optional[bool] `json:"billable,omitzero"`
+	Color            string         `json:"color,omitempty"`
+	Url              string         `json:"url,omitempty"`
 }
 
 type Tag struct {
-	Id       int    `json:"id,omitempty"`
-	Name     string `json:"name,omitempty"`
-	Billable bool   `json:"billable,omitempty"`
-	Url      string `json:"url,omitempty"`
+	Id         int            `json:"id,omitempty"`
+	Name       string         `json:"name,omitempty"`
+	Billable   Optional[bool] `json:"billable,omitzero"`
+	Url        string         `json:"url,omitempty"`
+	Entries    int            `json:"entries,omitempty"`
+	EntriesUrl string         `json:"entries_url,omitempty"`
+	CreatedAt  time.Time      `json:"created_at,omitzero"`
+	UpdatedAt  time.Time      `json:"updated_at,omitzero"`
+}
+
+type Timer struct {
+	Id            int            `json:"id,omitempty"`
+	State         string         `json:"state,omitempty"`
+	Seconds       int            `json:"seconds,omitempty"`
+	FormattedTime string         `json:"formatted_time,omitempty"`
+	Date          Date           `json:"date,omitzero"`
+	Description   string         `json:"description,omitempty"`
+	User          Participant    `json:"user,omitzero"`
+	Project       ProjectSummary `json:"project,omitzero"`
+	Url           string         `json:"url,omitempty"`
+	StartUrl      string         `json:"start_url,omitempty"`
+	PauseUrl      string         `json:"pause_url,omitempty"`
+	LogUrl        string         `json:"log_url,omitempty"`
+}
+
+type User struct {
+	Id                       int       `json:"id,omitempty"`
+	Email                    string    `json:"email,omitempty"`
+	FirstName                string    `json:"first_name,omitempty"`
+	LastName                 string    `json:"last_name,omitempty"`
+	ProfileImageUrl          string    `json:"profile_image_url,omitempty"`
+	Url                      string    `json:"url,omitempty"`
+	State                    string    `json:"state,omitempty"`
+	Role                     string    `json:"role,omitempty"`
+	ParticipatingProjects    int       `json:"participating_projects,omitempty"`
+	ParticipatingProjectsUrl string    `json:"participating_projects_url,omitempty"`
+	AccessibleProjects       int       `json:"accessible_projects,omitempty"`
+	AccessibleProjectsUrl    string    `json:"accessible_projects_url,omitempty"`
+	Entries                  int       `json:"entries,omitempty"`
+	EntriesUrl               string    `json:"entries_url,omitempty"`
+	Expenses                 int       `json:"expenses,omitempty"`
+	ExpensesUrl              string    `json:"expenses_url,omitempty"`
+	AddProjectAccessUrl      string    `json:"add_project_access,omitempty"`
+	RemoveProjectAccessUrl   string    `json:"remove_project_access,omitempty"`
+	CreatedAt                time.Time `json:"created_at,omitzero"`
+	UpdatedAt                time.Time `json:"updated_at,omitzero"`
 }
@@ -0,0 +1,97 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type UsersAPI struct {
+	freckle *Freckle
+}
+
+// describe an operation of this API for the interceptors
+func (u UsersAPI) op(method string) Operation {
+	return Operation{Resource: "UsersAPI", Method: method}
+}
+
+func (u UsersAPI) ListUsers(fns ...ParameterSetter) (UsersPage, error) {
+	result := emptyUsersPage(u.freckle)
+	return result, u.freckle.do(u.op("ListUsers"), "GET", "/users", parameters(fns), nil, result.onResponse)
+}
+
+func emptyUsersPage(f *Freckle) UsersPage {
+	return emptyPage[User](f, "UsersPage")
+}
+
+func (u UsersAPI) GetUser(id int) (User, error) {
+	var result User
+	return result, u.freckle.do(u.op("GetUser"), "GET", fmt.Sprintf("/users/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (u UsersAPI) CreateUser(email string, fns ...InputSetter) (User, error) {
+	is := inputs(fns)
+	is["email"] = email
+
+	var result User
+	return result, u.freckle.do(u.op("CreateUser"), "POST", "/users", nil, is,
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (u UsersAPI) EditUser(id int, fns ...InputSetter) (User, error) {
+	var result User
+	return result, u.freckle.do(u.op("EditUser"), "PUT", fmt.Sprintf("/users/%d", id), nil, inputs(fns),
+		func(data []byte, resp *http.Response) error {
+			return json.Unmarshal(data, &result)
+		})
+}
+
+func (u UsersAPI) DeactivateUser(id int) error {
+	return u.freckle.do(u.op("DeactivateUser"), "PUT", fmt.Sprintf("/users/%d/deactivate", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (u UsersAPI) ReactivateUser(id int) error {
+	return u.freckle.do(u.op("ReactivateUser"), "PUT", fmt.Sprintf("/users/%d/reactivate", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (u UsersAPI) DeleteUser(id int) error {
+	return u.freckle.do(u.op("DeleteUser"), "DELETE", fmt.Sprintf("/users/%d", id), nil, nil,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (u UsersAPI) GiveAccessToProjects(id int, projects ...int) error {
+	is := make(Inputs)
+	is["project_ids"] = projects
+
+	return u.freckle.do(u.op("GiveAccessToProjects"), "PUT", fmt.Sprintf("/users/%d/project_access/add", id), nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
+
+func (u UsersAPI) RevokeAccessToProjects(id int, projects ...int) error {
+	is := make(Inputs)
+	is["project_ids"] = projects
+
+	return u.freckle.do(u.op("RevokeAccessToProjects"), "PUT", fmt.Sprintf("/users/%d/project_access/remove", id), nil, is,
+		func(data []byte, resp *http.Response) error {
+			return nil
+		})
+}
@@ -0,0 +1,197 @@
+// Copyright 2014 - anova r&d bvba. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package freckle
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListUsers(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/users", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", fmt.Sprintf("<%s%s?page=2>; rel=\"next\"", ts.URL, r.URL.Path))
+		response(array_of_users)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	page, err := f.UsersAPI().ListUsers()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one user")
+	assert.Equal(t, "member", page.Items[0].Role, "User role mismatch")
+	assert.True(t, page.HasNext(), "Should have a next page")
+
+	page, err = page.Next()
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, 1, len(page.Items), "Should have one user")
+}
+
+func TestListUsersThroughChannel(t *testing.T) {
+	page := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(authenticated(t, "GET", "/users", func(w http.ResponseWriter, r *http.Request) {
+		page += 1
+		if page < 10 {
+			w.Header().Set("Link", fmt.Sprintf("<%s%s?page=%d>; rel=\"next\"", ts.URL, r.URL.Path, page+1))
+		}
+		response(array_of_users)(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	up, err := f.UsersAPI().ListUsers()
+	assert.Nil(t, err, "Error should be nil")
+	users := 0
+	for _ = range up.AllItems() {
+		users += 1
+	}
+	assert.Equal(t, 10, users, "Should have read 10 users")
+}
+
+func TestGetUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "GET", "/users/5538", response(single_user)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	user, err := f.UsersAPI().GetUser(5538)
+	assert.Nil(t, err, "Error should be nil")
+	assert.Equal(t, "john.test@test.com", user.Email, "User email mismatch")
+}
+
+func TestCreateUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "POST", "/users", response(single_user)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.UsersAPI().CreateUser("john.test@test.com", func(i Inputs) {
+		i["role"] = "member"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestEditUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/users/5538", response(single_user)))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	_, err := f.UsersAPI().EditUser(5538, func(i Inputs) {
+		i["role"] = "leader"
+	})
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeactivateUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/users/5538/deactivate", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.UsersAPI().DeactivateUser(5538)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestReactivateUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/users/5538/reactivate", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.UsersAPI().ReactivateUser(5538)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestDeleteUser(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "DELETE", "/users/5538", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.UsersAPI().DeleteUser(5538)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestGiveAccessToProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/users/5538/project_access/add", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string][]int
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body), "Body should be valid JSON")
+		assert.Equal(t, []int{1234, 4567}, body["project_ids"])
+		noContent()(w, r)
+	}))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.UsersAPI().GiveAccessToProjects(5538, 1234, 4567)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+func TestRevokeAccessToProjects(t *testing.T) {
+	ts := httptest.NewServer(authenticated(t, "PUT", "/users/5538/project_access/remove", noContent()))
+	defer ts.Close()
+
+	f := letsTestFreckle(ts)
+
+	err := f.UsersAPI().RevokeAccessToProjects(5538, 1234, 4567)
+	assert.Nil(t, err, "Error should be nil")
+}
+
+const array_of_users = `[
+  {
+    "id": 5538,
+    "email": "john.test@test.com",
+    "first_name": "John",
+    "last_name": "Test",
+    "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+    "url": "https://api.letsfreckle.com/v2/users/5538",
+    "state": "active",
+    "role": "member",
+    "participating_projects": 0,
+    "participating_projects_url": "https://api.letsfreckle.com/v2/users/5538/participating_projects",
+    "accessible_projects": 0,
+    "accessible_projects_url": "https://api.letsfreckle.com/v2/users/5538/accessible_projects",
+    "entries": 0,
+    "entries_url": "https://api.letsfreckle.com/v2/users/5538/entries",
+    "expenses": 0,
+    "expenses_url": "https://api.letsfreckle.com/v2/users/5538/expenses",
+    "add_project_access": "https://api.letsfreckle.com/v2/users/5538/project_access/add",
+    "remove_project_access": "https://api.letsfreckle.com/v2/users/5538/project_access/remove",
+    "created_at": "2010-06-09T20:44:57Z",
+    "updated_at": "2010-06-09T20:44:57Z"
+  }
+]`
+
+const single_user = `{
+  "id": 5538,
+  "email": "john.test@test.com",
+  "first_name": "John",
+  "last_name": "Test",
+  "profile_image_url": "https://api.letsfreckle.com/images/avatars/0000/0001/avatar.jpg",
+  "url": "https://api.letsfreckle.com/v2/users/5538",
+  "state": "active",
+  "role": "member",
+  "participating_projects": 0,
+  "participating_projects_url": "https://api.letsfreckle.com/v2/users/5538/participating_projects",
+  "accessible_projects": 0,
+  "accessible_projects_url": "https://api.letsfreckle.com/v2/users/5538/accessible_projects",
+  "entries": 0,
+  "entries_url": "https://api.letsfreckle.com/v2/users/5538/entries",
+  "expenses": 0,
+  "expenses_url": "https://api.letsfreckle.com/v2/users/5538/expenses",
+  "add_project_access": "https://api.letsfreckle.com/v2/users/5538/project_access/add",
+  "remove_project_access": "https://api.letsfreckle.com/v2/users/5538/project_access/remove",
+  "created_at": "2010-06-09T20:44:57Z",
+  "updated_at": "2010-06-09T20:44:57Z"
+}`